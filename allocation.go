@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// allocation granularity in minutes, so days aren't split into odd few-minute segments
+const allocationStep = 15
+
+// projectAllocator splits work days between projects, carrying rounding differences over to the following
+// days, so totals stay close to the configured weights across the whole date range
+type projectAllocator struct {
+	projects    []ProjectAllocation
+	totalWeight float64
+	// minutes allocated to each project so far
+	allocated []int
+	total     int
+}
+
+// newProjectAllocator returns nil when there are no projects to split the days between
+func newProjectAllocator(projects []ProjectAllocation) *projectAllocator {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	totalWeight := 0.0
+	for _, p := range projects {
+		totalWeight += p.Weight
+	}
+
+	return &projectAllocator{
+		projects:    projects,
+		totalWeight: totalWeight,
+		allocated:   make([]int, len(projects)),
+	}
+}
+
+// record counts already allocated minutes of entries, so following days are split against the whole range
+func (a *projectAllocator) record(entries []Entry) error {
+	if a == nil {
+		return nil
+	}
+	for _, entry := range entries {
+		i := slices.IndexFunc(a.projects, func(p ProjectAllocation) bool {
+			return p.ProjectId == entry.ProjectId && p.TaskId == entry.TaskId
+		})
+		if i < 0 {
+			continue
+		}
+		start, end, err := entryInterval(entry)
+		if err != nil {
+			return err
+		}
+		minutes := int(end.Sub(start).Minutes())
+		a.allocated[i] += minutes
+		a.total += minutes
+	}
+
+	return nil
+}
+
+// allocate splits day's minutes between projects
+func (a *projectAllocator) allocate(minutes int) []int {
+	total := a.total + minutes
+	shares := make([]int, len(a.projects))
+	// minutes each project is still missing to reach its weight after this day
+	deficit := func(i int) float64 {
+		return a.projects[i].Weight/a.totalWeight*float64(total) - float64(a.allocated[i]+shares[i])
+	}
+
+	assigned := 0
+	for i := range a.projects {
+		share := int(math.Round(deficit(i)/allocationStep)) * allocationStep
+		shares[i] = max(0, min(share, minutes-assigned))
+		assigned += shares[i]
+	}
+
+	// hand out the rounding leftover to the project most behind its weight
+	if assigned < minutes {
+		shares[a.mostBehind(deficit)] += minutes - assigned
+	}
+
+	for i := range a.projects {
+		a.allocated[i] += shares[i]
+	}
+	a.total = total
+
+	return shares
+}
+
+// mostBehind returns index of the project with the biggest deficit
+func (a *projectAllocator) mostBehind(deficit func(int) float64) int {
+	best := 0
+	for i := range a.projects {
+		if deficit(i) > deficit(best) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// split divides day's work blocks into contiguous per-project segments, in the order projects are configured
+func (a *projectAllocator) split(date time.Time, blocks []WorkBlock) []Entry {
+	minutes := 0
+	for _, block := range blocks {
+		minutes += int(block.end.Sub(block.start).Minutes())
+	}
+	shares := a.allocate(minutes)
+
+	var entries []Entry
+	p := 0
+	for _, block := range blocks {
+		cursor := block.start
+		for cursor.Before(block.end) {
+			// skip projects without time left for today
+			for p < len(shares)-1 && shares[p] <= 0 {
+				p++
+			}
+			end := cursor.Add(time.Duration(shares[p]) * time.Minute)
+			// the last project takes whatever is left of the day
+			if end.After(block.end) || p == len(shares)-1 {
+				end = block.end
+			}
+
+			project := a.projects[p]
+			entries = append(entries, newEntry(date, WorkBlock{start: cursor, end: end, note: block.note}, project.ProjectId, project.TaskId, project.Note))
+
+			shares[p] -= int(end.Sub(cursor).Minutes())
+			cursor = end
+		}
+	}
+
+	return entries
+}
+
+func validateSchedule(schedule Schedule) error {
+	for i, p := range schedule.Projects {
+		if p.ProjectId <= 0 {
+			return errors.New(fmt.Sprintf("schedule project #%d is missing 'projectId'", i+1))
+		}
+		if p.TaskId < 0 {
+			return errors.New(fmt.Sprintf("schedule project #%d has negative 'taskId'", i+1))
+		}
+		if p.Weight <= 0 {
+			return errors.New(fmt.Sprintf("schedule project #%d should have positive 'weight'", i+1))
+		}
+	}
+
+	return nil
+}
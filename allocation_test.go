@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestProjectAllocatorSplit(t *testing.T) {
+	projects := []ProjectAllocation{
+		{ProjectId: 1, Weight: 60},
+		{ProjectId: 2, TaskId: 7, Weight: 30},
+		{ProjectId: 3, Note: "internal", Weight: 10},
+	}
+	allocator := newProjectAllocator(projects)
+
+	totals := make(map[int]float64)
+	grandTotal := 0.0
+	for day := 1; day <= 20; day++ {
+		date := time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC)
+		start := date.Add(8*time.Hour + time.Duration(day)*time.Minute)
+		// 7h20 to 7h39 of work split around 30min break
+		work := time.Duration(440+day-1) * time.Minute
+		half := work / 2 / time.Minute * time.Minute
+		blocks := []WorkBlock{
+			{start: start, end: start.Add(half)},
+			{start: start.Add(half), end: start.Add(half + 30*time.Minute)},
+			{start: start.Add(half + 30*time.Minute), end: start.Add(work + 30*time.Minute)},
+		}
+
+		entries := allocator.split(date, blocks)
+
+		seen := make(map[int]bool)
+		for i, entry := range entries {
+			if i > 0 && entries[i-1].End != entry.Start {
+				t.Errorf("split() entries on %s should be contiguous, got %s after %s", entry.Date, entry.Start, entries[i-1].End)
+			}
+			if i > 0 && entries[i-1].ProjectId != entry.ProjectId && seen[entry.ProjectId] {
+				t.Errorf("split() project %d should be a single contiguous segment on %s", entry.ProjectId, entry.Date)
+			}
+			seen[entry.ProjectId] = true
+
+			s, _ := time.Parse("15:04", entry.Start)
+			e, _ := time.Parse("15:04", entry.End)
+			totals[entry.ProjectId] += e.Sub(s).Minutes()
+			grandTotal += e.Sub(s).Minutes()
+		}
+		if entries[0].Start != blocks[0].start.Format("15:04") || entries[len(entries)-1].End != blocks[2].end.Format("15:04") {
+			t.Errorf("split() should cover the whole day %s", date.Format("2006-01-02"))
+		}
+	}
+
+	for _, p := range projects {
+		want := p.Weight / 100 * grandTotal
+		if math.Abs(totals[p.ProjectId]-want) > allocationStep {
+			t.Errorf("split() project %d should have around %v minutes, got %v", p.ProjectId, want, totals[p.ProjectId])
+		}
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []ProjectAllocation
+		wantErr  bool
+	}{
+		{"Empty", nil, false},
+		{"Valid", []ProjectAllocation{{ProjectId: 1, Weight: 60}, {ProjectId: 2, TaskId: 3, Weight: 40}}, false},
+		{"MissingProject", []ProjectAllocation{{Weight: 60}}, true},
+		{"ZeroWeight", []ProjectAllocation{{ProjectId: 1}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateSchedule(Schedule{Projects: test.projects})
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateSchedule() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CachedResponse is a BambooHR response body stored on disk
+type CachedResponse struct {
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// cacheDir returns location of cached responses in user's cache directory
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("unable to find cache directory: %v", err))
+	}
+
+	return filepath.Join(dir, "bamboo"), nil
+}
+
+// cacheKey joins parts of the key eg. endpoint, employees and range into a file name. The key ends with hash of the
+// API, so responses of another BambooHR account or the fake server are never mixed in
+func cacheKey(parts ...string) string {
+	parts = append(parts, accountHash())
+	return strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(strings.Join(parts, "_")) + ".json"
+}
+
+// accountHash identifies BambooHR API and API key without storing the key in file names
+func accountHash() string {
+	sum := sha256.Sum256([]byte(baseUrl + "\x00" + apiKey))
+	return hex.EncodeToString(sum[:6])
+}
+
+// readCache returns cached response regardless of its age, or false when the key isn't cached
+func readCache(key string) (CachedResponse, bool) {
+	var cached CachedResponse
+	dir, err := cacheDir()
+	if err != nil {
+		return cached, false
+	}
+	file, err := os.ReadFile(filepath.Join(dir, key))
+	if err != nil {
+		return cached, false
+	}
+	if err := json.Unmarshal(file, &cached); err != nil {
+		return cached, false
+	}
+
+	return cached, true
+}
+
+func writeCache(key string, body []byte) error {
+	if !json.Valid(body) {
+		return errors.New("response isn't valid json")
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return errors.New(fmt.Sprintf("unable to create cache directory: %v", err))
+	}
+	file, _ := json.Marshal(CachedResponse{FetchedAt: clock(), Body: body})
+	if err := os.WriteFile(filepath.Join(dir, key), file, 0o600); err != nil {
+		return errors.New(fmt.Sprintf("unable to write cache: %v", err))
+	}
+
+	return nil
+}
+
+// clearCache removes cached responses whose key starts with the prefix, so reads after a write aren't stale
+func clearCache(prefix string) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, prefix+"*"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return errors.New(fmt.Sprintf("unable to clear cache: %v", err))
+		}
+	}
+
+	return nil
+}
+
+// cachedFetch returns cached response younger than 'cacheTtl', otherwise it fetches and caches a fresh one. In
+// offline mode, the cached response is returned regardless of its age and the API is never called
+func cachedFetch(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	cached, ok := readCache(key)
+	if offline {
+		if !ok {
+			return nil, errors.New("there is no cached response for the selected range, run it once without 'offline'")
+		}
+		return cached.Body, nil
+	}
+	if ok && cacheTtl > 0 && clock().Sub(cached.FetchedAt) < cacheTtl {
+		return cached.Body, nil
+	}
+
+	body, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if cacheTtl > 0 {
+		// failing cache only costs another request next time, so the error is ignored
+		_ = writeCache(key, body)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mluksic/bamboo/fakebamboo"
+)
+
+// useCacheDir points user's cache directory to a temporary directory
+func useCacheDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func useCache(t *testing.T, ttl time.Duration, offlineMode bool) {
+	t.Helper()
+	originalTtl, originalOffline := cacheTtl, offline
+	cacheTtl, offline = ttl, offlineMode
+	t.Cleanup(func() { cacheTtl, offline = originalTtl, originalOffline })
+}
+
+func TestCachedFetch(t *testing.T) {
+	useCacheDir(t)
+	useCache(t, 10*time.Minute, false)
+	pinClock(t, "2025-03-14 10:30")
+
+	calls := 0
+	fetch := func(body string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			calls++
+			return []byte(body), nil
+		}
+	}
+	key := cacheKey("timesheet", "12,34", "2025-03-01", "2025-04-01")
+
+	if got, err := cachedFetch(key, fetch(`[1]`)); err != nil || string(got) != `[1]` {
+		t.Fatalf("cachedFetch() = %s, %v, want fetched response", got, err)
+	}
+	if got, err := cachedFetch(key, fetch(`[2]`)); err != nil || string(got) != `[1]` || calls != 1 {
+		t.Errorf("cachedFetch() = %s, %v after %d calls, want cached response", got, err, calls)
+	}
+
+	pinClock(t, "2025-03-14 10:45")
+	if got, err := cachedFetch(key, fetch(`[3]`)); err != nil || string(got) != `[3]` || calls != 2 {
+		t.Errorf("cachedFetch() = %s, %v after %d calls, want refetched response after TTL", got, err, calls)
+	}
+
+	// offline mode serves even stale responses
+	useCache(t, time.Minute, true)
+	pinClock(t, "2025-03-15 10:45")
+	if got, err := cachedFetch(key, fetch(`[4]`)); err != nil || string(got) != `[3]` || calls != 2 {
+		t.Errorf("cachedFetch() = %s, %v after %d calls, want stale cached response in offline mode", got, err, calls)
+	}
+	if _, err := cachedFetch(cacheKey("whos_out", "2025-03-01", "2025-04-01"), fetch(`[]`)); err == nil || calls != 2 {
+		t.Errorf("cachedFetch() should return error without calling API for uncached key in offline mode")
+	}
+}
+
+func TestCachedFetchDisabled(t *testing.T) {
+	useCacheDir(t)
+	useCache(t, 0, false)
+
+	calls := 0
+	fetch := func() ([]byte, error) {
+		calls++
+		return []byte(`[]`), nil
+	}
+	cachedFetch("projects_12.json", fetch)
+	cachedFetch("projects_12.json", fetch)
+
+	if calls != 2 {
+		t.Errorf("cachedFetch() called API %d times, want 2 with disabled cache", calls)
+	}
+	if _, ok := readCache("projects_12.json"); ok {
+		t.Errorf("cachedFetch() should not write cache when it's disabled")
+	}
+}
+
+func TestCachedFetchError(t *testing.T) {
+	useCacheDir(t)
+	useCache(t, 10*time.Minute, false)
+
+	_, err := cachedFetch("projects_12.json", func() ([]byte, error) { return nil, errors.New("API returned 500") })
+
+	if err == nil {
+		t.Errorf("cachedFetch() should return fetch error")
+	}
+	if _, ok := readCache("projects_12.json"); ok {
+		t.Errorf("cachedFetch() should not cache failed response")
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	useCacheDir(t)
+	useCache(t, 10*time.Minute, false)
+	for _, key := range []string{"timesheet_12_2025-03-01_2025-04-01.json", "timesheet_34_2025-03-01_2025-04-01.json", "projects_12.json"} {
+		if err := writeCache(key, []byte(`[]`)); err != nil {
+			t.Fatalf("writeCache() error = %v", err)
+		}
+	}
+
+	if err := clearCache("timesheet"); err != nil {
+		t.Fatalf("clearCache() error = %v", err)
+	}
+
+	if _, ok := readCache("timesheet_12_2025-03-01_2025-04-01.json"); ok {
+		t.Errorf("clearCache() should remove cached timesheets")
+	}
+	if _, ok := readCache("projects_12.json"); !ok {
+		t.Errorf("clearCache() should keep cached projects")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	originalBaseUrl, originalApiKey := baseUrl, apiKey
+	t.Cleanup(func() { baseUrl, apiKey = originalBaseUrl, originalApiKey })
+	baseUrl, apiKey = defaultBaseUrl, "key"
+
+	got := cacheKey("timesheet", "12,34", "2025-03-01", "2025-04-01")
+
+	if want := "timesheet_12,34_2025-03-01_2025-04-01_"; !strings.HasPrefix(got, want) || !strings.HasSuffix(got, ".json") {
+		t.Errorf("cacheKey() = %q, want %q followed by account hash", got, want)
+	}
+	baseUrl = "http://127.0.0.1:8081" + fakebamboo.BasePath
+	if fake := cacheKey("timesheet", "12,34", "2025-03-01", "2025-04-01"); fake == got {
+		t.Errorf("cacheKey() of another base URL = %q, want different key", fake)
+	}
+	baseUrl, apiKey = defaultBaseUrl, "another"
+	if another := cacheKey("timesheet", "12,34", "2025-03-01", "2025-04-01"); another == got {
+		t.Errorf("cacheKey() of another API key = %q, want different key", another)
+	}
+}
+
+func TestErrorResponseIsNotCached(t *testing.T) {
+	useCacheDir(t)
+	useCache(t, 10*time.Minute, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": "internal error"}`))
+	}))
+	defer server.Close()
+	originalBaseUrl, originalStart, originalEnd := baseUrl, startDate, endDate
+	baseUrl, startDate, endDate = server.URL, "2025-03-01", "2025-04-01"
+	t.Cleanup(func() { baseUrl, startDate, endDate = originalBaseUrl, originalStart, originalEnd })
+
+	if _, _, err := NewCsvHolidays("").fetchTeamTimeOff(); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("fetchTeamTimeOff() error = %v, want 500 error", err)
+	}
+	if _, err := fetchEmployeesHours([]int{12}); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("fetchEmployeesHours() error = %v, want 500 error", err)
+	}
+	if _, ok := readCache(cacheKey("whos_out", startDate, endDate)); ok {
+		t.Errorf("error response of whos_out shouldn't be cached")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ExitMissing is returned by the 'check' command when workdays without tracked hours are found, so cron
+// jobs can tell missing timesheets apart from failures, which exit with 1
+const ExitMissing = 2
+
+// WebhookBody is posted to the notification webhook, 'text' is understood by most chat tools eg. Slack
+type WebhookBody struct {
+	Text string `json:"text"`
+}
+
+// checkWindow is the number of calendar days before today fetched by the 'check' command, so the last workdays
+// are found even after a long time off
+func checkWindow(days int) int {
+	return days*2 + 42
+}
+
+// checkRange returns range of the last N workdays before the end date, but not before the earliest date. Days are
+// skipped by the same rules as generated entries eg. weekends, public holidays, time off and excluded days, only
+// logged hours don't skip the day
+func checkRange(end time.Time, days int, report Report, earliest time.Time) (time.Time, time.Time) {
+	start := end
+	for found := 0; found < days && start.After(earliest); {
+		start = start.AddDate(0, 0, -1)
+		if isWorkday(evaluateDay(start, report)) {
+			found++
+		}
+	}
+
+	return start, end
+}
+
+func isWorkday(decision DayDecision) bool {
+	for _, rule := range decision.Rules {
+		if rule.Name != RuleLoggedHours {
+			return false
+		}
+	}
+
+	return true
+}
+
+// missingDays returns days without tracked hours, which aren't excluded by any day rule eg. time off
+func missingDays(report Report, start time.Time, end time.Time) []time.Time {
+	var missing []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if !evaluateDay(d, report).excluded() {
+			missing = append(missing, d)
+		}
+	}
+
+	return missing
+}
+
+// checkMessage renders the notification about missing days
+func checkMessage(missing []time.Time) string {
+	days := make([]string, 0, len(missing))
+	for _, d := range missing {
+		days = append(days, fmt.Sprintf("%s (%s)", d.Format("2006-01-02"), d.Weekday()))
+	}
+
+	return fmt.Sprintf("BambooHR timesheet is missing %d workdays: %s. Log them before payroll closes", len(missing), strings.Join(days, ", "))
+}
+
+// processCheck reports workdays without tracked hours to stdout and the webhook, and exits with ExitMissing
+// when there are any
+func processCheck(report Report, start time.Time, end time.Time, webhook string) {
+	missing := missingDays(report, start, end)
+	if len(missing) == 0 {
+		fmt.Printf("All workdays between %s and %s are logged \n", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+		return
+	}
+
+	message := checkMessage(missing)
+	fmt.Println(message)
+	if webhook != "" {
+		if err := notifyWebhook(webhook, message); err != nil {
+			fmt.Printf("Unable to send notification: %v \n", err)
+			os.Exit(1)
+		}
+	}
+
+	os.Exit(ExitMissing)
+}
+
+// notifyWebhook posts the message as JSON to a generic webhook URL
+func notifyWebhook(url string, message string) error {
+	reqBody, _ := json.Marshal(WebhookBody{Text: message})
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to create POST request: %v", err))
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to post to webhook: %v", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.New(fmt.Sprintf("webhook returned %d", resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckRange(t *testing.T) {
+	originalHolidays, originalTimeOff, originalExcluded := publicHolidays, timeOff, excludedDays
+	publicHolidays = map[string]string{"2024-12-25": "Božič", "2024-12-26": "Dan samostojnosti in enotnosti"}
+	timeOff = map[string]string{"2024-12-19": timeOffDay, "2024-12-20": timeOffDay}
+	excludedDays = map[string]bool{"2024-12-24": true}
+	t.Cleanup(func() { publicHolidays, timeOff, excludedDays = originalHolidays, originalTimeOff, originalExcluded })
+	// logged days are still workdays
+	report := Report{map[string]DayReport{"2024-12-27": {workHours: 8}}, 8}
+	// Monday after Christmas
+	end := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+	earliest := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		days      int
+		earliest  time.Time
+		wantStart string
+	}{
+		{"PreviousWorkday", 1, earliest, "2024-12-27"},
+		{"SkipsWeekendHolidaysAndExcludedDays", 2, earliest, "2024-12-23"},
+		{"SkipsTimeOff", 3, earliest, "2024-12-18"},
+		{"StopsAtEarliest", 10, time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC), "2024-12-16"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, gotEnd := checkRange(end, test.days, report, test.earliest)
+
+			if start.Format("2006-01-02") != test.wantStart || !gotEnd.Equal(end) {
+				t.Errorf("checkRange() = %s - %s, want %s - %s", start.Format("2006-01-02"), gotEnd.Format("2006-01-02"),
+					test.wantStart, end.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestMissingDays(t *testing.T) {
+	originalHolidays, originalTimeOff, originalExcluded := publicHolidays, timeOff, excludedDays
+	publicHolidays = map[string]string{"2024-12-25": "Božič", "2024-12-26": "Dan samostojnosti in enotnosti"}
+	timeOff = map[string]string{"2024-12-27": timeOffDay}
+	excludedDays = map[string]bool{"2024-12-20": true}
+	t.Cleanup(func() { publicHolidays, timeOff, excludedDays = originalHolidays, originalTimeOff, originalExcluded })
+
+	report := Report{map[string]DayReport{"2024-12-23": {workHours: 8}}, 8}
+	start := time.Date(2024, 12, 19, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	var got []string
+	for _, d := range missingDays(report, start, end) {
+		got = append(got, d.Format("2006-01-02"))
+	}
+
+	want := []string{"2024-12-19", "2024-12-24"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("missingDays() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckMessage(t *testing.T) {
+	missing := []time.Time{time.Date(2024, 12, 19, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)}
+
+	got := checkMessage(missing)
+
+	want := "BambooHR timesheet is missing 2 workdays: 2024-12-19 (Thursday), 2024-12-24 (Tuesday). Log them before payroll closes"
+	if got != want {
+		t.Errorf("checkMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	var received WebhookBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if received.Text == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	if err := notifyWebhook(server.URL, "Timesheet is missing"); err != nil {
+		t.Fatalf("notifyWebhook() error = %v", err)
+	}
+	if received.Text != "Timesheet is missing" {
+		t.Errorf("webhook received %q, want %q", received.Text, "Timesheet is missing")
+	}
+	if err := notifyWebhook(server.URL, "fail"); err == nil {
+		t.Errorf("notifyWebhook() should return error when webhook fails")
+	}
+}
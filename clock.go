@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errUnreachable is returned when BambooHR can't be reached, so clock events can be recorded offline
+var errUnreachable = errors.New("BambooHR is unreachable")
+
+// daily target shown by the 'now' command on workdays
+const dailyTarget = 8 * time.Hour
+
+// ClockState is stored locally between clock commands, so a forgotten clock-out can be detected
+type ClockState struct {
+	EmployeeId int `json:"employeeId"`
+	// ClockedIn is the start of the running entry, zero when clocked out
+	ClockedIn time.Time `json:"clockedIn"`
+	// BreakStart is set while the employee is on a break
+	BreakStart time.Time `json:"breakStart"`
+	// Offline is set when the running entry was recorded in the offline journal
+	Offline bool `json:"offline"`
+}
+
+// ClockBody is sent to BambooHR clock in and clock out endpoints
+type ClockBody struct {
+	ProjectId int    `json:"projectId,omitempty"`
+	TaskId    int    `json:"taskId,omitempty"`
+	Note      string `json:"note,omitempty"`
+	Date      string `json:"date"`
+	Start     string `json:"start,omitempty"`
+	End       string `json:"end,omitempty"`
+	Timezone  string `json:"timezone"`
+}
+
+// clockStatePath returns location of the clock state file in user's config directory
+func clockStatePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("unable to find config directory: %v", err))
+	}
+
+	return filepath.Join(dir, "bamboo", "clock.json"), nil
+}
+
+// loadClockState returns empty state when the file doesn't exist yet
+func loadClockState(path string) (ClockState, error) {
+	var state ClockState
+	file, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, errors.New(fmt.Sprintf("unable to read clock state: %v", err))
+	}
+	if err := json.Unmarshal(file, &state); err != nil {
+		return state, errors.New(fmt.Sprintf("unable to parse clock state '%s': %v", path, err))
+	}
+
+	return state, nil
+}
+
+func saveClockState(path string, state ClockState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.New(fmt.Sprintf("unable to create clock state directory: %v", err))
+	}
+	body, _ := json.MarshalIndent(state, "", "  ")
+	if err := os.WriteFile(path, body, 0o600); err != nil {
+		return errors.New(fmt.Sprintf("unable to write clock state: %v", err))
+	}
+
+	return nil
+}
+
+// forgottenClockOut returns the running entry's start, when the employee clocked in before the given day
+// and never clocked out
+func forgottenClockOut(state ClockState, now time.Time) (time.Time, bool) {
+	if state.ClockedIn.IsZero() {
+		return time.Time{}, false
+	}
+	clockedIn := state.ClockedIn.In(location)
+
+	return clockedIn, clockedIn.Format("2006-01-02") != now.In(location).Format("2006-01-02")
+}
+
+// clockTime returns now, or the given HH:MM time on the same day
+func clockTime(now time.Time, at string) (time.Time, error) {
+	now = now.In(location).Truncate(time.Minute)
+	if at == "" {
+		return now, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", now.Format("2006-01-02")+" "+at, location)
+	if err != nil {
+		return time.Time{}, errors.New(fmt.Sprintf("unable to parse time '%s', expected HH:MM", at))
+	}
+
+	return t, nil
+}
+
+// processClock runs 'in', 'out' and 'break' commands and keeps the local clock state in sync
+func processClock(action string, at string) {
+	path, err := clockStatePath()
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	state, err := loadClockState(path)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if state.EmployeeId != 0 && state.EmployeeId != employeeId {
+		state = ClockState{}
+	}
+	state.EmployeeId = employeeId
+
+	now := clock()
+	if start, forgotten := forgottenClockOut(state, now); forgotten {
+		if action != ActionOut || at == "" {
+			fmt.Printf("You clocked in on %s at %s and never clocked out. Close it with '--at HH:MM out' first. Aborting \n",
+				start.Format("2006-01-02"), start.Format("15:04"))
+			os.Exit(1)
+		}
+		// close the forgotten entry on the day it was started
+		now = start
+	}
+	t, err := clockTime(now, at)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	switch action {
+	case ActionIn:
+		if !state.ClockedIn.IsZero() {
+			fmt.Printf("You're already clocked in since %s. Aborting \n", formatLocalTime(state.ClockedIn))
+			os.Exit(1)
+		}
+		body := ClockBody{ProjectId: projectId, TaskId: taskId, Note: note, Date: t.Format("2006-01-02"), Start: t.Format("15:04"), Timezone: location.String()}
+		err := postClock("clock_in", body)
+		state.Offline = errors.Is(err, errUnreachable)
+		if state.Offline {
+			entry := Entry{Date: body.Date, Start: body.Start, ProjectId: projectId, TaskId: taskId, Note: note}
+			err = appendJournal(JournalEntry{Entry: entry, Source: JournalClock})
+			fmt.Println("BambooHR is unreachable, clock in is recorded in the offline journal. Use 'sync' once you're online")
+		}
+		if err != nil {
+			fmt.Printf("Unable to clock in: %v. Aborting \n", err)
+			os.Exit(1)
+		}
+		if !state.BreakStart.IsZero() {
+			fmt.Printf("Your break took %s \n", formatDuration(t.Sub(state.BreakStart)))
+		}
+		state.ClockedIn, state.BreakStart = t, time.Time{}
+		fmt.Printf("Clocked in at %s \n", t.Format("15:04"))
+	case ActionOut, ActionBreak:
+		if state.ClockedIn.IsZero() {
+			fmt.Println("You're not clocked in. Aborting")
+			os.Exit(1)
+		}
+		if !t.After(state.ClockedIn) {
+			fmt.Printf("Clock out time %s should be after clock in at %s. Aborting \n", t.Format("15:04"), formatLocalTime(state.ClockedIn))
+			os.Exit(1)
+		}
+		body := ClockBody{Date: t.Format("2006-01-02"), End: t.Format("15:04"), Timezone: location.String()}
+		var err error
+		if state.Offline {
+			err = closeJournalEntry(body.Date, body.End)
+		} else {
+			// the running entry is in BambooHR, so it can't be closed offline
+			err = postClock("clock_out", body)
+		}
+		if err != nil {
+			fmt.Printf("Unable to clock out: %v. Aborting \n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Clocked out at %s after %s \n", t.Format("15:04"), formatDuration(t.Sub(state.ClockedIn)))
+		state.ClockedIn, state.Offline = time.Time{}, false
+		if action == ActionBreak {
+			state.BreakStart = t
+			fmt.Println("Enjoy your break, use 'in' to continue")
+		}
+	}
+
+	if err := saveClockState(path, state); err != nil {
+		fmt.Printf("%v \n", err)
+		os.Exit(1)
+	}
+}
+
+func postClock(endpoint string, body ClockBody) error {
+	url := apiUrl("/time_tracking/employees/%d/%s", employeeId, endpoint)
+	if offline {
+		return fmt.Errorf("%w: offline mode", errUnreachable)
+	}
+
+	reqBody, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to create POST request: %v", err))
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to read response body: %v", err))
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized)")
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: API returned %d", errUnreachable, resp.StatusCode)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.New(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(respBody)))
+	}
+
+	// cached timesheet doesn't have the clock entry yet
+	return clearCache("timesheet")
+}
+
+// processNow prints today's worked time against the daily target, including the running entry
+func processNow(entries []TimeEntry, report Report) {
+	now := clock()
+	path, err := clockStatePath()
+	if err == nil {
+		if state, err := loadClockState(path); err == nil && (state.EmployeeId == 0 || state.EmployeeId == employeeId) {
+			if start, forgotten := forgottenClockOut(state, now); forgotten {
+				fmt.Printf("Warning: you clocked in on %s at %s and never clocked out \n", start.Format("2006-01-02"), start.Format("15:04"))
+			}
+		}
+	}
+
+	worked, running := workedToday(entries, now)
+	target := dailyTarget
+	date, _ := time.Parse("2006-01-02", now.In(location).Format("2006-01-02"))
+	for _, rule := range evaluateDay(date, report).Rules {
+		if rule.Name == RuleWeekend || rule.Name == RuleHoliday || rule.Name == RuleTimeOff {
+			target = 0
+		}
+	}
+
+	fmt.Printf("Worked today: %s of %s \n", formatDuration(worked), formatDuration(target))
+	if running {
+		fmt.Println("You're clocked in")
+	}
+	if remaining := target - worked; remaining > 0 {
+		fmt.Printf("Remaining: %s, done at %s if you keep working \n", formatDuration(remaining), now.Add(remaining).In(location).Format("15:04"))
+	} else if target > 0 {
+		fmt.Printf("Overtime: %s \n", formatDuration(-remaining))
+	}
+}
+
+// workedToday sums today's entries. Entries without end are still running and count until now
+func workedToday(entries []TimeEntry, now time.Time) (time.Duration, bool) {
+	var worked time.Duration
+	running := false
+	date := now.In(location).Format("2006-01-02")
+	for _, entry := range entries {
+		if entry.Date != date {
+			continue
+		}
+		if !entry.Start.IsZero() && entry.End.IsZero() {
+			running = true
+			if now.After(entry.Start) {
+				worked += now.Sub(entry.Start)
+			}
+			continue
+		}
+		worked += time.Duration(entry.Hours * float64(time.Hour))
+	}
+
+	return worked.Truncate(time.Minute), running
+}
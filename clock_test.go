@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClockStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bamboo", "clock.json")
+
+	state, err := loadClockState(path)
+	if err != nil || !reflect.DeepEqual(ClockState{}, state) {
+		t.Fatalf("loadClockState() = %v, %v, want empty state for missing file", state, err)
+	}
+
+	want := ClockState{EmployeeId: 123, ClockedIn: time.Date(2025, 3, 14, 8, 2, 0, 0, time.UTC)}
+	if err := saveClockState(path, want); err != nil {
+		t.Fatalf("saveClockState() error = %v", err)
+	}
+	got, err := loadClockState(path)
+	if err != nil {
+		t.Fatalf("loadClockState() error = %v", err)
+	}
+	if got.EmployeeId != want.EmployeeId || !got.ClockedIn.Equal(want.ClockedIn) || !got.BreakStart.IsZero() {
+		t.Errorf("loadClockState() = %v, want %v", got, want)
+	}
+}
+
+func TestForgottenClockOut(t *testing.T) {
+	loc := useLjubljana(t)
+	at := func(value string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", value, loc)
+		return t
+	}
+
+	tests := []struct {
+		name      string
+		state     ClockState
+		now       time.Time
+		forgotten bool
+	}{
+		{"ClockedOut", ClockState{}, at("2025-03-14 09:00"), false},
+		{"ClockedInToday", ClockState{ClockedIn: at("2025-03-14 08:00")}, at("2025-03-14 17:00"), false},
+		{"ClockedInYesterday", ClockState{ClockedIn: at("2025-03-13 08:00")}, at("2025-03-14 08:30"), true},
+		// 23:30 UTC is already the next day in Ljubljana
+		{"EmployeeTimeZone", ClockState{ClockedIn: at("2025-03-14 08:00")}, time.Date(2025, 3, 14, 23, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, forgotten := forgottenClockOut(test.state, test.now)
+
+			if forgotten != test.forgotten {
+				t.Errorf("forgottenClockOut() = %v, want %v", forgotten, test.forgotten)
+			}
+		})
+	}
+}
+
+func TestClockTime(t *testing.T) {
+	loc := useLjubljana(t)
+	now := time.Date(2025, 3, 14, 16, 31, 45, 0, time.UTC)
+
+	got, err := clockTime(now, "")
+	if err != nil || !got.Equal(time.Date(2025, 3, 14, 17, 31, 0, 0, loc)) {
+		t.Errorf("clockTime() = %v, %v, want now truncated to minutes", got, err)
+	}
+	got, err = clockTime(now, "08:15")
+	if err != nil || !got.Equal(time.Date(2025, 3, 14, 8, 15, 0, 0, loc)) {
+		t.Errorf("clockTime() = %v, %v, want 08:15 on the same day", got, err)
+	}
+	if _, err := clockTime(now, "8am"); err == nil {
+		t.Errorf("clockTime() should return error for invalid time")
+	}
+}
+
+func TestWorkedToday(t *testing.T) {
+	loc := useLjubljana(t)
+	at := func(clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", "2025-03-14 "+clock, loc)
+		return t
+	}
+	entries := []TimeEntry{
+		{Date: "2025-03-13", Start: at("08:00").AddDate(0, 0, -1), End: at("16:00").AddDate(0, 0, -1), Hours: 8},
+		{Date: "2025-03-14", Start: at("08:00"), End: at("12:00"), Hours: 4},
+		{Date: "2025-03-14", Start: at("12:30")},
+	}
+
+	worked, running := workedToday(entries, at("14:15"))
+
+	if worked != 5*time.Hour+45*time.Minute || !running {
+		t.Errorf("workedToday() = %v, %v, want 5h45m and running entry", worked, running)
+	}
+}
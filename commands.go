@@ -0,0 +1,511 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Command is a subcommand with its own flags, help text and examples
+type Command struct {
+	Name string
+	// Args describes positional arguments in the usage line eg. <file>
+	Args     string
+	Summary  string
+	Help     string
+	Examples []string
+	// Flags are names of the command's flags from flagDefs
+	Flags []string
+	// Hidden commands aren't listed in the usage
+	Hidden bool
+	Run    func(args []string)
+}
+
+// flagDefs registers every flag of the app, commands pick their flags by name. Defaults come from the config file
+var flagDefs = map[string]func(fs *flag.FlagSet, config *Config){
+	"apiKey": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&apiKey, "apiKey", config.ApiToken, "Your BambooHR API key")
+	},
+	"employeeId": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&employeeId, "employeeId", config.EmployeeId, "Your BambooHR employee ID")
+	},
+	"baseUrl": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&baseUrl, "baseUrl", cmp.Or(config.BaseUrl, defaultBaseUrl), "BambooHR API base URL eg. of the fake server started by fake-server")
+	},
+	"offline": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&offline, "offline", false, "Read entries, time off and projects from cache only, nothing is pushed to BambooHR")
+	},
+	"cacheTtl": func(fs *flag.FlagSet, config *Config) {
+		fs.DurationVar(&cacheTtl, "cacheTtl", 10*time.Minute, "How long fetched entries, time off and projects are cached, 0 disables the cache")
+	},
+	"timezone": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&timezone, "timezone", config.Timezone, "Employee's IANA time zone eg. Europe/Ljubljana, defaults to the time zone of tracked entries")
+	},
+	"start": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&startDate, "start", "", "Start date filter (YYYY-MM-DD, today or yesterday)")
+	},
+	"end": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&endDate, "end", "", "End date filter (YYYY-MM-DD, today or yesterday), excluded unless 'inclusive' is set")
+	},
+	"inclusive": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&inclusive, "inclusive", false, "Include the 'end' date in the date range")
+	},
+	"month": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&month, "month", "", "Month filter (YYYY-MM, this-month or last-month) instead of start and end dates")
+	},
+	"week": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&week, "week", "", "ISO week filter (YYYY-Www, this-week or last-week) instead of start and end dates")
+	},
+	"year": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&year, "year", 0, "Year for fetching required hours")
+	},
+	"groupBy": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&groupBy, "groupBy", GroupByMonth, "Group required hours by week, month or quarter")
+	},
+	"excludeDays": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&excludeDays, "excludeDays", "", "Comma-separated list of days (YYYY-MM-DD,YYYY-MM-DD) eg PTO, Collective Leave etc.")
+	},
+	"employeeIds": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&employeeIds, "employeeIds", "", "Comma-separated list of employee IDs (12,34) reported on by team mode")
+	},
+	"roster": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&roster, "roster", "", "CSV file with 'id' and 'name' columns of employees reported on by team mode")
+	},
+	"force": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&force, "force", false, "Populate work hours without confirmation")
+	},
+	"projectId": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&projectId, "projectId", config.ProjectId, "BambooHR project ID assigned to generated entries")
+	},
+	"taskId": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&taskId, "taskId", config.TaskId, "BambooHR task ID assigned to generated entries, requires 'projectId'")
+	},
+	"note": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&note, "note", config.Note, "Note added to generated entries")
+	},
+	"format": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&importFormat, "format", FormatBamboo, "Import file format: bamboo (CSV or JSON), toggl, clockify or harvest")
+	},
+	"round": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&roundMinutes, "round", 0, "Round imported entries to the nearest N minutes")
+	},
+	"allowHolidays": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&allowHolidays, "allowHolidays", false, "Import entries on holidays and time off days")
+	},
+	"generator": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&generator, "generator", GeneratorTemplate, "Source of generated work blocks: template, git or ics")
+	},
+	"gitRepos": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&gitRepos, "gitRepos", strings.Join(config.Schedule.Git.Repos, ","), "Comma-separated list of local git repositories for git generator")
+	},
+	"gitAuthor": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&gitAuthor, "gitAuthor", config.Schedule.Git.Author, "Commit author email for git generator")
+	},
+	"gitPadding": func(fs *flag.FlagSet, config *Config) {
+		// padding in config file is validated on start
+		padding, _ := time.ParseDuration(cmp.Or(config.Schedule.Git.Padding, "30m"))
+		fs.DurationVar(&gitPadding, "gitPadding", padding, "Time added before the first and after the last commit of the day")
+	},
+	"icsFile": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&icsFile, "icsFile", config.Schedule.Ics.File, "iCalendar file for ics generator")
+	},
+	"icsNotes": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&icsNotes, "icsNotes", config.Schedule.Ics.Notes, "Copy calendar event titles into entry notes")
+	},
+	"detailed": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&detailed, "detailed", false, "List individual entries with their type, note and approval status")
+	},
+	"unapproved": func(fs *flag.FlagSet, config *Config) {
+		fs.BoolVar(&unapproved, "unapproved", false, "List only entries which aren't approved yet, used with 'detailed'")
+	},
+	"type": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&entryType, "type", "", "List only entries of the given type eg. timeEntry, used with 'detailed'")
+	},
+	"country": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&country, "country", cmp.Or(config.Compliance.Country, "SI"), "Country of labor-law rules for generated and imported entries eg. SI, DE, AT or none, it selects compliance rules only - holidays are always Slovenian public holidays")
+	},
+	"at": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&clockAt, "at", "", "Time (HH:MM) of clock in or out instead of now")
+	},
+	"days": func(fs *flag.FlagSet, config *Config) {
+		fs.IntVar(&checkDays, "days", 5, "Number of last workdays checked for missing hours")
+	},
+	"webhook": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&webhook, "webhook", "", "Webhook URL notified about missing hours")
+	},
+	"addr": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&addr, "addr", "127.0.0.1:8080", "Address the server listens on")
+	},
+	"maxDay": func(fs *flag.FlagSet, config *Config) {
+		fs.DurationVar(&maxDay, "maxDay", 10*time.Hour, "Max hours logged per day")
+	},
+	"breakAfter": func(fs *flag.FlagSet, config *Config) {
+		fs.DurationVar(&breakAfter, "breakAfter", 6*time.Hour, "Max continuous work without a break")
+	},
+	"normalHours": func(fs *flag.FlagSet, config *Config) {
+		fs.StringVar(&normalHours, "normalHours", "06:00-20:00", "Normal working hours (HH:MM-HH:MM)")
+	},
+}
+
+var (
+	apiFlags       = []string{"apiKey", "employeeId", "baseUrl", "offline", "cacheTtl", "timezone"}
+	rangeFlags     = []string{"start", "end", "inclusive", "month", "week"}
+	entryFlags     = []string{"projectId", "taskId", "note"}
+	generatorFlags = []string{"generator", "gitRepos", "gitAuthor", "gitPadding", "icsFile", "icsNotes"}
+)
+
+// commands are listed in the usage in this order
+var commands = []*Command{
+	{
+		Name:    ActionList,
+		Summary: "List tracked hours of every day in the range",
+		Help: "Lists every day of the range. Days without hours show why they're skipped by 'add', or MISSING when hours " +
+			"should have been logged. With 'detailed', every entry is listed with its type, note and approval status.\n" +
+			"With 'employeeIds' or 'roster', hours of the whole team are listed side by side.",
+		Examples: []string{"bamboo list --month last-month", "bamboo list --month this-month --detailed --unapproved", "bamboo list --week this-week --roster team.csv"},
+		Flags:    slices.Concat(apiFlags, rangeFlags, []string{"excludeDays", "detailed", "unapproved", "type", "employeeIds", "roster"}),
+		Run:      runList,
+	},
+	{
+		Name:     ActionBalance,
+		Summary:  "Compare tracked with required hours and list missing days",
+		Help:     "Compares tracked hours with 8 hours on every workday, which isn't weekend, public holiday or time off.\nWith 'employeeIds' or 'roster', every employee of the team is compared.",
+		Examples: []string{"bamboo balance --month last-month", "bamboo balance --month last-month --employeeIds 12,34,56"},
+		Flags:    slices.Concat(apiFlags, rangeFlags, []string{"excludeDays", "employeeIds", "roster"}),
+		Run:      runBalance,
+	},
+	{
+		Name:     ActionAdd,
+		Summary:  "Generate and push entries for days without tracked hours",
+		Help:     "Generates work entries for every workday of the range without tracked hours, asks for confirmation and pushes them to BambooHR.\nWeekends, public holidays, time off and excluded days are skipped.",
+		Examples: []string{"bamboo add --month last-month", "bamboo add --start 2024-09-01 --end today --inclusive --excludeDays 2024-09-16", "bamboo add --week this-week --generator git"},
+		Flags:    slices.Concat(apiFlags, rangeFlags, entryFlags, generatorFlags, []string{"excludeDays", "force", "country"}),
+		Run:      runAdd,
+	},
+	{
+		Name:     ActionImport,
+		Args:     "<file>",
+		Summary:  "Push entries from a CSV or JSON file or a time tracker export",
+		Help:     "Validates entries from the file and pushes them to BambooHR. The range defaults to the range covered by the file.",
+		Examples: []string{"bamboo import entries.csv", "bamboo import --format toggl --round 15 toggl.csv"},
+		Flags:    slices.Concat(apiFlags, rangeFlags, entryFlags, []string{"force", "format", "round", "allowHolidays", "country"}),
+		Run:      runImport,
+	},
+	{
+		Name:     ActionExport,
+		Args:     "<file>",
+		Summary:  "Export tracked entries to a CSV, JSON or iCalendar file",
+		Help:     "Exports tracked entries of the range, the format is selected by the file extension (.csv, .json or .ics).",
+		Examples: []string{"bamboo export --month last-month timesheet.csv", "bamboo export --month this-month calendar.ics"},
+		Flags:    slices.Concat(apiFlags, rangeFlags),
+		Run:      runExport,
+	},
+	{
+		Name:     ActionWhy,
+		Args:     "<date>",
+		Summary:  "Explain why entries are or aren't generated for a day",
+		Help:     "Prints every rule which applies to the date and the generator's final decision.",
+		Examples: []string{"bamboo why 2025-03-14", "bamboo why yesterday"},
+		Flags:    slices.Concat(apiFlags, []string{"excludeDays"}),
+		Run:      runWhy,
+	},
+	{
+		Name:     ActionLint,
+		Summary:  "Check tracked entries for overlaps, long days and missing breaks",
+		Help:     "Checks tracked entries of the range and exits with status 1 when issues are found.",
+		Examples: []string{"bamboo lint --month last-month", "bamboo lint --month this-month --maxDay 9h --normalHours 07:00-19:00"},
+		Flags:    slices.Concat(apiFlags, rangeFlags, []string{"maxDay", "breakAfter", "normalHours"}),
+		Run:      runLint,
+	},
+	{
+		Name:    ActionCheck,
+		Summary: "Report the last workdays without tracked hours, suitable for cron",
+		Help: fmt.Sprintf("Checks the last workdays before today and reports days without tracked hours. It exits with status %d "+
+			"when something is missing and 1 on failure.", ExitMissing),
+		Examples: []string{"bamboo check --days 10", "bamboo check --webhook https://hooks.slack.com/services/T000/B000/XXXX"},
+		Flags:    slices.Concat(apiFlags, []string{"excludeDays", "days", "webhook"}),
+		Run:      runCheck,
+	},
+	{
+		Name:     ActionIn,
+		Summary:  "Clock in",
+		Help:     "Starts a running entry in BambooHR. When BambooHR is unreachable, clock in is recorded in the offline journal.",
+		Examples: []string{"bamboo in", "bamboo in --at 08:15 --projectId 5"},
+		Flags:    slices.Concat(apiFlags, entryFlags, []string{"at"}),
+		Run:      runClock(ActionIn),
+	},
+	{
+		Name:     ActionOut,
+		Summary:  "Clock out",
+		Help:     "Ends the running entry. Use 'at' to close an entry you forgot to clock out of.",
+		Examples: []string{"bamboo out", "bamboo out --at 17:30"},
+		Flags:    slices.Concat(apiFlags, []string{"at"}),
+		Run:      runClock(ActionOut),
+	},
+	{
+		Name:     ActionBreak,
+		Summary:  "Clock out for a break",
+		Help:     "Ends the running entry and starts a break, use 'in' to continue.",
+		Examples: []string{"bamboo break", "bamboo break --at 12:00"},
+		Flags:    slices.Concat(apiFlags, []string{"at"}),
+		Run:      runClock(ActionBreak),
+	},
+	{
+		Name:     ActionNow,
+		Summary:  "Show today's worked time against the daily target",
+		Help:     "Shows today's worked time including the running entry, and when the daily target is reached.",
+		Examples: []string{"bamboo now"},
+		Flags:    slices.Concat(apiFlags, []string{"excludeDays"}),
+		Run:      runNow,
+	},
+	{
+		Name:     ActionRecord,
+		Args:     "<date> <start> <end>",
+		Summary:  "Record an interval in the offline journal",
+		Help:     "Records a manually entered interval in the offline journal, use 'sync' to push it to BambooHR.",
+		Examples: []string{"bamboo record 2025-03-14 08:00 12:00"},
+		Flags:    slices.Concat(entryFlags, []string{"timezone"}),
+		Run:      runRecord,
+	},
+	{
+		Name:     ActionSync,
+		Summary:  "Push the offline journal to BambooHR",
+		Help:     "Pushes journal entries which BambooHR doesn't have yet. Entries in conflict are kept until they're resolved manually.",
+		Examples: []string{"bamboo sync", "bamboo sync --force"},
+		Flags:    slices.Concat(apiFlags, []string{"force", "country"}),
+		Run:      runSync,
+	},
+	{
+		Name:     ActionProjects,
+		Summary:  "List projects and tasks you can track time on",
+		Examples: []string{"bamboo projects"},
+		Flags:    []string{"apiKey", "employeeId", "baseUrl", "offline", "cacheTtl"},
+		Run:      runProjects,
+	},
+	{
+		Name:     ActionRequired,
+		Summary:  "Show required hours by week, month or quarter",
+		Help:     "Calculates required hours of the year or range from workdays and public holidays.",
+		Examples: []string{"bamboo required --year 2024", "bamboo required --year 2025 --groupBy quarter", "bamboo required --month last-month"},
+		Flags:    slices.Concat([]string{"year", "groupBy"}, rangeFlags),
+		Run:      runRequired,
+	},
+	{
+		Name:     ActionServe,
+		Summary:  "Start the local web dashboard",
+		Help:     "Starts a local web dashboard with a month calendar and JSON API over list, required, balance, preview and submit.",
+		Examples: []string{"bamboo serve", "bamboo serve --addr 127.0.0.1:9000"},
+		Flags:    slices.Concat(apiFlags, entryFlags, generatorFlags, []string{"excludeDays", "country", "addr"}),
+		Run:      runServe,
+	},
+	{
+		Name:     ActionFakeServer,
+		Args:     "[seed file]",
+		Summary:  "Start in-memory fake BambooHR for local testing",
+		Examples: []string{"bamboo fake-server --addr 127.0.0.1:8081 seed.json"},
+		Flags:    []string{"addr"},
+		Hidden:   true,
+		Run: func(args []string) {
+			processFakeServer(addr, firstArg(args))
+		},
+	},
+	{
+		Name:    ActionCompletion,
+		Args:    "<bash|zsh|fish>",
+		Summary: "Print shell completion script",
+		Help: "Prints completion script of commands and flags for the shell. Besides commands and flags, it completes " +
+			"recent months, countries and project IDs of projects cached by 'projects'.",
+		Examples: []string{
+			"source <(bamboo completion bash)",
+			"bamboo completion zsh > \"${fpath[1]}/_bamboo\"",
+			"bamboo completion fish > ~/.config/fish/completions/bamboo.fish",
+		},
+	},
+	{
+		Name:    ActionComplete,
+		Args:    "<flag>",
+		Summary: "Print completion values of the flag",
+		Hidden:  true,
+		Run:     runComplete,
+	},
+	{
+		Name:     ActionHelp,
+		Args:     "[command]",
+		Summary:  "Show help of the command",
+		Examples: []string{"bamboo help add"},
+	},
+}
+
+// help and completion list commands themselves, so they're assigned on init to avoid initialization cycle
+func init() {
+	findCommand(ActionHelp).Run = runHelp
+	findCommand(ActionCompletion).Run = runCompletion
+}
+
+func findCommand(name string) *Command {
+	i := slices.IndexFunc(commands, func(c *Command) bool { return c.Name == name })
+	if i < 0 {
+		return nil
+	}
+
+	return commands[i]
+}
+
+// flagSet returns the command's flags, which fail on unknown flags instead of exiting
+func (c *Command) flagSet(config *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, name := range c.Flags {
+		flagDefs[name](fs, config)
+	}
+
+	return fs
+}
+
+// allFlags registers every flag, so globals of flags, which the command doesn't have, still get their defaults
+func allFlags(config *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet("bamboo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, name := range slices.Sorted(maps.Keys(flagDefs)) {
+		flagDefs[name](fs, config)
+	}
+
+	return fs
+}
+
+// splitCommand returns the command and its arguments. Flags may come before the command as well eg.
+// 'bamboo --month last-month list', so values of flags are skipped while looking for it
+func splitCommand(args []string, all *flag.FlagSet) (string, []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return arg, slices.Concat(args[:i], args[i+1:])
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if hasValue || isBoolFlag(all.Lookup(name)) {
+			continue
+		}
+		// skip the value of the flag
+		i++
+	}
+
+	return "", args
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+
+	return ok && b.IsBoolFlag()
+}
+
+// parseArgs parses flags, which may be mixed with positional arguments eg. 'import file.csv --force'
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// expectArgs aborts when the command got more positional arguments than it accepts
+func expectArgs(command string, args []string, max int) {
+	if len(args) > max {
+		fmt.Printf("Unexpected argument '%s', see 'bamboo help %s'. Aborting \n", args[max], command)
+		os.Exit(1)
+	}
+}
+
+// printUsage lists commands which aren't hidden
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: bamboo <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		if !c.Hidden {
+			fmt.Fprintf(w, "  %-10s %s\n", c.Name, c.Summary)
+		}
+	}
+	fmt.Fprintf(w, "\nRun 'bamboo help <command>' for its flags and examples\n")
+}
+
+// printHelp prints the command's usage, help text, examples and flags
+func printHelp(w io.Writer, c *Command, config *Config) {
+	usage := "bamboo " + c.Name
+	if len(c.Flags) > 0 {
+		usage += " [flags]"
+	}
+	if c.Args != "" {
+		usage += " " + c.Args
+	}
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", usage, cmp.Or(c.Help, c.Summary))
+	if len(c.Examples) > 0 {
+		fmt.Fprintf(w, "\nExamples:\n")
+		for _, example := range c.Examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+	if len(c.Flags) > 0 {
+		fmt.Fprintf(w, "\nFlags:\n")
+		fs := c.flagSet(config)
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+}
+
+// runCommand parses the command's flags and runs it, unknown flags and invalid values abort the program
+func runCommand(args []string, config *Config) {
+	all := allFlags(config)
+	name, args := splitCommand(args, all)
+	if name == "" {
+		printUsage(os.Stdout)
+		if slices.ContainsFunc(args, func(arg string) bool { return slices.Contains([]string{"-h", "-help", "--help"}, arg) }) {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+	command := findCommand(name)
+	if command == nil {
+		fmt.Printf("Unknown command '%s'\n\n", name)
+		printUsage(os.Stdout)
+		os.Exit(1)
+	}
+
+	positional, err := parseArgs(command.flagSet(config), args)
+	if errors.Is(err, flag.ErrHelp) {
+		printHelp(os.Stdout, command, config)
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Printf("Invalid flags: %v, see 'bamboo help %s'. Aborting \n", err, name)
+		os.Exit(1)
+	}
+
+	configure(config)
+	command.Run(positional)
+}
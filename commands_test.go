@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	all := allFlags(&Config{})
+	tests := []struct {
+		name        string
+		args        []string
+		wantCommand string
+		wantArgs    []string
+	}{
+		{"command first", []string{"list", "--month", "last-month"}, "list", []string{"--month", "last-month"}},
+		{"flags first", []string{"--month", "last-month", "list"}, "list", []string{"--month", "last-month"}},
+		{"bool flag before command", []string{"--force", "add", "--week", "this-week"}, "add", []string{"--force", "--week", "this-week"}},
+		{"flag with value after equal sign", []string{"-month=2025-03", "balance"}, "balance", []string{"-month=2025-03"}},
+		{"positional argument", []string{"import", "entries.csv", "--force"}, "import", []string{"entries.csv", "--force"}},
+		{"no command", []string{"--month", "list"}, "", []string{"--month", "list"}},
+		{"no command after terminator", []string{"--", "list"}, "", []string{"--", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, args := splitCommand(tt.args, all)
+			if command != tt.wantCommand || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("splitCommand() = %q %v, want %q %v", command, args, tt.wantCommand, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"flags after positional argument", ActionImport, []string{"entries.csv", "--force", "--round", "15"}, []string{"entries.csv"}, false},
+		{"several positional arguments", ActionRecord, []string{"2025-03-14", "--note", "Review", "08:00", "12:00"}, []string{"2025-03-14", "08:00", "12:00"}, false},
+		{"flag of another command", ActionProjects, []string{"--month", "last-month"}, nil, true},
+		{"unknown flag", ActionList, []string{"--verbose"}, nil, true},
+		{"invalid value", ActionCheck, []string{"--days", "many"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(findCommand(tt.command).flagSet(&Config{}), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandFlagsAreDefined(t *testing.T) {
+	for _, command := range commands {
+		if command.Run == nil {
+			t.Errorf("command %q has no Run", command.Name)
+		}
+		for _, name := range command.Flags {
+			if flagDefs[name] == nil {
+				t.Errorf("command %q uses undefined flag %q", command.Name, name)
+			}
+		}
+	}
+}
+
+func TestPrintHelp(t *testing.T) {
+	var out bytes.Buffer
+
+	printHelp(&out, findCommand(ActionImport), &Config{})
+
+	for _, want := range []string{"Usage: bamboo import [flags] <file>", "bamboo import entries.csv", "-format", "-round"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printHelp() doesn't contain %q: %s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "-webhook") {
+		t.Errorf("printHelp() contains flag of another command: %s", out.String())
+	}
+}
+
+func TestPrintUsage(t *testing.T) {
+	var out bytes.Buffer
+
+	printUsage(&out)
+
+	if !strings.Contains(out.String(), "balance") || strings.Contains(out.String(), ActionFakeServer) {
+		t.Errorf("printUsage() should list commands except hidden ones: %s", out.String())
+	}
+}
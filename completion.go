@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+var shells = []string{ShellBash, ShellZsh, ShellFish}
+
+// recentMonths is the number of months before this month completed for 'month'
+const recentMonths = 12
+
+// completedFlags have their values completed by the hidden __complete command
+var completedFlags = []string{"month", "country", "projectId", "generator", "format", "groupBy"}
+
+// completionValues returns values of the flag offered by shell completion
+func completionValues(name string) []string {
+	switch name {
+	case "month":
+		values := []string{"this-month", "last-month"}
+		current := today().AddDate(0, 0, 1-today().Day())
+		for i := range recentMonths + 1 {
+			values = append(values, current.AddDate(0, -i, 0).Format("2006-01"))
+		}
+		return values
+	case "country":
+		return slices.Sorted(maps.Keys(complianceDefaults))
+	case "projectId":
+		return cachedProjectIds()
+	case "generator":
+		return generators
+	case "format":
+		return importFormats
+	case "groupBy":
+		return groupings
+	}
+
+	return nil
+}
+
+// cachedProjectIds returns IDs of employee's projects from the cache, completion never calls BambooHR. The cache key
+// comes from 'apiKey' and 'employeeId' of the config file, as completion scripts only pass the completed flag, so
+// the ones given on the command line aren't seen
+func cachedProjectIds() []string {
+	cached, ok := readCache(cacheKey("projects", strconv.Itoa(employeeId)))
+	if !ok {
+		return nil
+	}
+	var projects []Project
+	if err := json.Unmarshal(cached.Body, &projects); err != nil {
+		return nil
+	}
+	var ids []string
+	for _, project := range projects {
+		ids = append(ids, strconv.Itoa(project.Id))
+	}
+
+	return ids
+}
+
+// writeCompletion writes completion script of the shell for visible commands and their flags
+func writeCompletion(w io.Writer, shell string) error {
+	var visible []*Command
+	for _, c := range commands {
+		if !c.Hidden {
+			visible = append(visible, c)
+		}
+	}
+
+	switch shell {
+	case ShellBash:
+		writeBashCompletion(w, visible)
+	case ShellZsh:
+		writeZshCompletion(w, visible)
+	case ShellFish:
+		writeFishCompletion(w, visible)
+	default:
+		return errors.New(fmt.Sprintf("unsupported shell '%s', use one of: %s", shell, strings.Join(shells, ", ")))
+	}
+
+	return nil
+}
+
+// commandFlags returns the command's flags sorted by name, defaults of the flags aren't used by completion
+func commandFlags(c *Command) []*flag.Flag {
+	var flags []*flag.Flag
+	c.flagSet(&Config{}).VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f)
+	})
+
+	return flags
+}
+
+func commandNames(commands []*Command) []string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func writeBashCompletion(w io.Writer, commands []*Command) {
+	// values of these flags are skipped while looking for the command, the same way as by splitCommand
+	var valueFlags []string
+	allFlags(&Config{}).VisitAll(func(f *flag.Flag) {
+		if !isBoolFlag(f) {
+			valueFlags = append(valueFlags, "-"+f.Name, "--"+f.Name)
+		}
+	})
+	var patterns []string
+	for _, name := range completedFlags {
+		patterns = append(patterns, "-"+name, "--"+name)
+	}
+
+	fmt.Fprintf(w, "# bash completion for bamboo, generated by 'bamboo completion bash'\n")
+	fmt.Fprintf(w, "_bamboo() {\n")
+	fmt.Fprintf(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\" command=\"\" i\n")
+	fmt.Fprintf(w, "    case \"$prev\" in\n")
+	fmt.Fprintf(w, "    %s)\n", strings.Join(patterns, "|"))
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"$(\"${COMP_WORDS[0]}\" %s \"${prev##*-}\" 2>/dev/null)\" -- \"$cur\"))\n", ActionComplete)
+	fmt.Fprintf(w, "        return\n")
+	fmt.Fprintf(w, "        ;;\n")
+	fmt.Fprintf(w, "    esac\n")
+	// flags may come before the command eg. 'bamboo --month last-month list'
+	fmt.Fprintf(w, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(w, "        case \"${COMP_WORDS[i]}\" in\n")
+	fmt.Fprintf(w, "        --) break ;;\n")
+	fmt.Fprintf(w, "        -*=*) ;;\n")
+	fmt.Fprintf(w, "        %s)\n", strings.Join(valueFlags, "|"))
+	fmt.Fprintf(w, "            ((i++))\n")
+	// bash splits '--month=2025-03' into '--month', '=' and '2025-03'
+	fmt.Fprintf(w, "            [[ \"${COMP_WORDS[i]}\" == \"=\" ]] && ((i++))\n")
+	fmt.Fprintf(w, "            ;;\n")
+	fmt.Fprintf(w, "        -*) ;;\n")
+	fmt.Fprintf(w, "        *)\n")
+	fmt.Fprintf(w, "            command=\"${COMP_WORDS[i]}\"\n")
+	fmt.Fprintf(w, "            break\n")
+	fmt.Fprintf(w, "            ;;\n")
+	fmt.Fprintf(w, "        esac\n")
+	fmt.Fprintf(w, "    done\n")
+	fmt.Fprintf(w, "    if [[ -z \"$command\" ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(commandNames(commands), " "))
+	fmt.Fprintf(w, "        return\n")
+	fmt.Fprintf(w, "    fi\n")
+	fmt.Fprintf(w, "    local words\n")
+	fmt.Fprintf(w, "    case \"$command\" in\n")
+	for _, c := range commands {
+		var words []string
+		for _, f := range commandFlags(c) {
+			words = append(words, "--"+f.Name)
+		}
+		switch c.Name {
+		case ActionHelp:
+			words = commandNames(commands)
+		case ActionCompletion:
+			words = shells
+		}
+		fmt.Fprintf(w, "    %s) words=\"%s\" ;;\n", c.Name, strings.Join(words, " "))
+	}
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "}\n")
+	// fall back to file names eg. for 'import' and 'export'
+	fmt.Fprintf(w, "complete -o default -F _bamboo bamboo\n")
+}
+
+func writeZshCompletion(w io.Writer, commands []*Command) {
+	escape := strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:")
+	fmt.Fprintf(w, "#compdef bamboo\n")
+	fmt.Fprintf(w, "# zsh completion for bamboo, generated by 'bamboo completion zsh'\n\n")
+	fmt.Fprintf(w, "_bamboo_values() {\n")
+	fmt.Fprintf(w, "    local -a values\n")
+	fmt.Fprintf(w, "    values=(${(f)\"$(${words[1]} %s $1 2>/dev/null)\"})\n", ActionComplete)
+	fmt.Fprintf(w, "    compadd -a values\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "_bamboo() {\n")
+	fmt.Fprintf(w, "    local -a commands\n")
+	fmt.Fprintf(w, "    commands=(\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.Name, escape.Replace(c.Summary))
+	}
+	fmt.Fprintf(w, "    )\n")
+	fmt.Fprintf(w, "    if (( CURRENT == 2 )); then\n")
+	fmt.Fprintf(w, "        _describe 'command' commands\n")
+	fmt.Fprintf(w, "        return\n")
+	fmt.Fprintf(w, "    fi\n")
+	fmt.Fprintf(w, "    case $words[2] in\n")
+	for _, c := range commands {
+		specs := []string{}
+		for _, f := range commandFlags(c) {
+			spec := fmt.Sprintf("'--%s[%s]", f.Name, escape.Replace(f.Usage))
+			if slices.Contains(completedFlags, f.Name) {
+				spec += fmt.Sprintf(":%s:_bamboo_values %s", f.Name, f.Name)
+			} else if !isBoolFlag(f) {
+				spec += fmt.Sprintf(":%s:_default", f.Name)
+			}
+			specs = append(specs, spec+"'")
+		}
+		switch c.Name {
+		case ActionHelp:
+			specs = append(specs, "'1:command:("+strings.Join(commandNames(commands), " ")+")'")
+		case ActionCompletion:
+			specs = append(specs, "'1:shell:("+strings.Join(shells, " ")+")'")
+		default:
+			if c.Args != "" {
+				specs = append(specs, "'*:argument:_files'")
+			}
+		}
+		fmt.Fprintf(w, "    %s)\n", c.Name)
+		fmt.Fprintf(w, "        _arguments")
+		for _, spec := range specs {
+			fmt.Fprintf(w, " \\\n            %s", spec)
+		}
+		fmt.Fprintf(w, "\n        ;;\n")
+	}
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "compdef _bamboo bamboo\n")
+}
+
+func writeFishCompletion(w io.Writer, commands []*Command) {
+	escape := strings.NewReplacer("\\", "\\\\", "'", "\\'")
+	fmt.Fprintf(w, "# fish completion for bamboo, generated by 'bamboo completion fish'\n")
+	fmt.Fprintf(w, "complete -c bamboo -f\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c bamboo -n __fish_use_subcommand -a %s -d '%s'\n", c.Name, escape.Replace(c.Summary))
+	}
+
+	// flags are completed once for all commands, which have them
+	usages := map[string]*flag.Flag{}
+	flagCommands := map[string][]string{}
+	for _, c := range commands {
+		for _, f := range commandFlags(c) {
+			usages[f.Name] = f
+			flagCommands[f.Name] = append(flagCommands[f.Name], c.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(usages)) {
+		f := usages[name]
+		line := fmt.Sprintf("complete -c bamboo -n '__fish_seen_subcommand_from %s' -l %s -d '%s'",
+			strings.Join(flagCommands[name], " "), name, escape.Replace(f.Usage))
+		if slices.Contains(completedFlags, name) {
+			line += fmt.Sprintf(" -x -a '(bamboo %s %s)'", ActionComplete, name)
+		} else if !isBoolFlag(f) {
+			line += " -r"
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	var withFiles []string
+	for _, c := range commands {
+		if c.Args != "" && c.Name != ActionHelp && c.Name != ActionCompletion {
+			withFiles = append(withFiles, c.Name)
+		}
+	}
+	fmt.Fprintf(w, "complete -c bamboo -n '__fish_seen_subcommand_from %s' -F\n", strings.Join(withFiles, " "))
+	fmt.Fprintf(w, "complete -c bamboo -n '__fish_seen_subcommand_from %s' -a '%s'\n", ActionHelp, strings.Join(commandNames(commands), " "))
+	fmt.Fprintf(w, "complete -c bamboo -n '__fish_seen_subcommand_from %s' -a '%s'\n", ActionCompletion, strings.Join(shells, " "))
+}
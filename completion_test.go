@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompletionValues(t *testing.T) {
+	pinClock(t, "2025-03-14 10:30")
+	useCacheDir(t)
+	originalEmployeeId := employeeId
+	employeeId = 12
+	t.Cleanup(func() { employeeId = originalEmployeeId })
+	if err := writeCache(cacheKey("projects", "12"), []byte(`[{"id": 5, "name": "Development"}, {"id": 7, "name": "Support"}]`)); err != nil {
+		t.Fatalf("writeCache() error = %v", err)
+	}
+
+	months := completionValues("month")
+	if len(months) != recentMonths+3 || months[0] != "this-month" || months[2] != "2025-03" || months[len(months)-1] != "2024-03" {
+		t.Errorf("completionValues(month) = %v, want shorthands and months from 2025-03 to 2024-03", months)
+	}
+	if got := completionValues("country"); !slices.Equal(got, []string{"AT", "DE", "SI", "none"}) {
+		t.Errorf("completionValues(country) = %v, want countries of compliance rules", got)
+	}
+	if got := completionValues("projectId"); !slices.Equal(got, []string{"5", "7"}) {
+		t.Errorf("completionValues(projectId) = %v, want cached project IDs", got)
+	}
+	if got := completionValues("apiKey"); got != nil {
+		t.Errorf("completionValues(apiKey) = %v, want no values", got)
+	}
+}
+
+func TestCachedProjectIdsWithoutCache(t *testing.T) {
+	useCacheDir(t)
+
+	if got := cachedProjectIds(); got != nil {
+		t.Errorf("cachedProjectIds() = %v, want no IDs", got)
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	tests := []struct {
+		shell  string
+		want   []string
+		hidden string
+		check  []string
+	}{
+		{ShellBash, []string{"complete -o default -F _bamboo bamboo", "import) words=\"--allowHolidays", "-month|--month", "help) words=\"list balance"}, "fake-server)", []string{"bash", "-n"}},
+		{ShellZsh, []string{"#compdef bamboo", "'--month[", ":month:_bamboo_values month'", "'--force[Populate work hours without confirmation]'"}, "'fake-server:", []string{"zsh", "-n"}},
+		{ShellFish, []string{"-a check -d 'Report the last", "-l month", "-a '(bamboo __complete month)'", "__fish_seen_subcommand_from import export why record' -F"}, "-a fake-server", []string{"fish", "-n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeCompletion(&out, tt.shell); err != nil {
+				t.Fatalf("writeCompletion() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("completion doesn't contain %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), tt.hidden) {
+				t.Errorf("completion contains hidden command")
+			}
+			// check syntax of the script, when the shell is installed
+			if _, err := exec.LookPath(tt.check[0]); err == nil {
+				cmd := exec.Command(tt.check[0], tt.check[1:]...)
+				cmd.Stdin = &out
+				if output, err := cmd.CombinedOutput(); err != nil {
+					t.Errorf("%s rejected completion script: %v %s", tt.shell, err, output)
+				}
+			}
+		})
+	}
+
+	if err := writeCompletion(&bytes.Buffer{}, "powershell"); err == nil {
+		t.Errorf("writeCompletion(powershell) should return error")
+	}
+}
+
+func TestBashCompletionWithLeadingFlags(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash isn't installed")
+	}
+	var script bytes.Buffer
+	if err := writeCompletion(&script, ShellBash); err != nil {
+		t.Fatalf("writeCompletion() error = %v", err)
+	}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"bamboo lis", "list"},
+		{"bamboo --month last-month lis", "list"},
+		{"bamboo --offline --month=2025-03 lis", "list"},
+		{"bamboo --month last-month list --det", "--detailed"},
+		{"bamboo --offline list --det", "--detailed"},
+		{"bamboo --month = 2025-03 list --det", "--detailed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			words := strings.Fields(tt.line)
+			cmd := exec.Command("bash", "-c", script.String()+`
+COMP_WORDS=("$@"); COMP_CWORD=$(($# - 1)); _bamboo; echo "${COMPREPLY[@]}"`, "bash")
+			cmd.Args = append(cmd.Args, words...)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash error = %v %s", err, output)
+			}
+			if got := strings.TrimSpace(string(output)); got != tt.want {
+				t.Errorf("completion of %q = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
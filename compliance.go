@@ -0,0 +1,370 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ComplianceDailyRest = "daily rest"
+	ComplianceBreaks    = "breaks"
+	ComplianceWeeklyMax = "weekly max"
+)
+
+// gaps shorter than this don't count as a break
+const minBreakGap = 15 * time.Minute
+
+// ComplianceRules are labor-law limits which generated and imported entries have to respect, zero values
+// aren't checked
+type ComplianceRules struct {
+	Country string
+	// DailyRest is the minimum rest between the end of one work day and the start of the next one
+	DailyRest time.Duration
+	// WeeklyMax is the max time worked within an ISO week, including overtime
+	WeeklyMax time.Duration
+	// Breaks are required once the day is longer than the threshold, the longest matching threshold applies
+	Breaks []BreakRule
+}
+
+type BreakRule struct {
+	After  time.Duration
+	Length time.Duration
+}
+
+// Violation is a single broken compliance rule
+type Violation struct {
+	Date   string
+	Rule   string
+	Detail string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s - %s", v.Date, v.Rule, v.Detail)
+}
+
+// complianceDefaults hold statutory limits per country
+var complianceDefaults = map[string]ComplianceRules{
+	// ZDR-1: 12h rest between days, 30min break in a work day of at least 4h, 40h week with max 8h overtime
+	"SI": {
+		DailyRest: 12 * time.Hour,
+		WeeklyMax: 48 * time.Hour,
+		Breaks:    []BreakRule{{After: 4 * time.Hour, Length: 30 * time.Minute}},
+	},
+	// ArbZG: 11h rest between days, 30min break after 6h and 45min after 9h, 48h week
+	"DE": {
+		DailyRest: 11 * time.Hour,
+		WeeklyMax: 48 * time.Hour,
+		Breaks:    []BreakRule{{After: 6 * time.Hour, Length: 30 * time.Minute}, {After: 9 * time.Hour, Length: 45 * time.Minute}},
+	},
+	// AZG: 11h rest between days, 30min break after 6h, 48h week on average
+	"AT": {
+		DailyRest: 11 * time.Hour,
+		WeeklyMax: 48 * time.Hour,
+		Breaks:    []BreakRule{{After: 6 * time.Hour, Length: 30 * time.Minute}},
+	},
+	// disables compliance checks
+	"none": {},
+}
+
+// resolveCompliance returns country's default rules, overridden by durations from the config file
+func resolveCompliance(country string, config ComplianceConfig) (ComplianceRules, error) {
+	key := strings.ToUpper(country)
+	if strings.EqualFold(country, "none") {
+		key = "none"
+	}
+	rules, ok := complianceDefaults[key]
+	if !ok {
+		countries := slices.Sorted(maps.Keys(complianceDefaults))
+		return ComplianceRules{}, errors.New(fmt.Sprintf("unknown country '%s', use one of: %s", country, strings.Join(countries, ", ")))
+	}
+	rules.Country = key
+
+	var err error
+	if config.DailyRest != "" {
+		if rules.DailyRest, err = time.ParseDuration(config.DailyRest); err != nil {
+			return ComplianceRules{}, errors.New(fmt.Sprintf("unable to parse daily rest '%s', expected duration eg. 12h", config.DailyRest))
+		}
+	}
+	if config.WeeklyMax != "" {
+		if rules.WeeklyMax, err = time.ParseDuration(config.WeeklyMax); err != nil {
+			return ComplianceRules{}, errors.New(fmt.Sprintf("unable to parse weekly max '%s', expected duration eg. 48h", config.WeeklyMax))
+		}
+	}
+	if config.Breaks != nil {
+		rules.Breaks = nil
+		for _, b := range config.Breaks {
+			after, err := time.ParseDuration(b.After)
+			if err != nil {
+				return ComplianceRules{}, errors.New(fmt.Sprintf("unable to parse break threshold '%s', expected duration eg. 6h", b.After))
+			}
+			length, err := time.ParseDuration(b.Length)
+			if err != nil {
+				return ComplianceRules{}, errors.New(fmt.Sprintf("unable to parse break length '%s', expected duration eg. 30m", b.Length))
+			}
+			rules.Breaks = append(rules.Breaks, BreakRule{After: after, Length: length})
+		}
+	}
+
+	return rules, nil
+}
+
+// workDay summarizes a single day for compliance checks
+type workDay struct {
+	start  time.Time
+	end    time.Time
+	worked time.Duration
+	breaks time.Duration
+	// logged days come from BambooHR report, so their breaks aren't known
+	logged bool
+}
+
+// newWorkDay summarizes day's entries. Only gaps of at least 15min between entries count as breaks, logged entries
+// are always work
+func newWorkDay(day []Entry) (workDay, error) {
+	var blocks []WorkBlock
+	for _, entry := range day {
+		start, end, err := entryInterval(entry)
+		if err != nil {
+			return workDay{}, err
+		}
+		blocks = append(blocks, WorkBlock{start: start, end: end})
+	}
+	if len(blocks) == 0 {
+		return workDay{}, errors.New("day without entries")
+	}
+	slices.SortFunc(blocks, func(a, b WorkBlock) int { return a.start.Compare(b.start) })
+
+	wd := workDay{start: blocks[0].start, end: blocks[0].end}
+	for i, block := range blocks {
+		wd.worked += block.end.Sub(block.start)
+		if gap := block.start.Sub(wd.end); i > 0 && gap >= minBreakGap {
+			wd.breaks += gap
+		}
+		wd.end = maxTime(wd.end, block.end)
+	}
+
+	return wd, nil
+}
+
+// reportTimeline summarizes days already logged in BambooHR
+func reportTimeline(report Report) map[string]workDay {
+	timeline := make(map[string]workDay)
+	for date, day := range report.days {
+		timeline[date] = workDay{
+			start:  day.start,
+			end:    day.end,
+			worked: time.Duration(day.workHours * float64(time.Hour)),
+			logged: true,
+		}
+	}
+
+	return timeline
+}
+
+// checkDay checks the day's breaks and rest from the previous day. Rest before the following day is checked only
+// when it's already logged, otherwise it's checked with the following day
+func (c ComplianceRules) checkDay(date string, timeline map[string]workDay) []Violation {
+	var violations []Violation
+	day, ok := timeline[date]
+	if !ok {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+
+	if required := c.requiredBreak(day.worked); !day.logged && day.breaks < required {
+		violations = append(violations, Violation{date, ComplianceBreaks, fmt.Sprintf(
+			"%s worked with %s of breaks, %s requires %s", formatDuration(day.worked), formatDuration(day.breaks), c.Country, formatDuration(required))})
+	}
+
+	previous, ok := timeline[t.AddDate(0, 0, -1).Format("2006-01-02")]
+	if ok {
+		if v, broken := c.checkRest(date, previous, day); broken {
+			violations = append(violations, v)
+		}
+	}
+	next, ok := timeline[t.AddDate(0, 0, 1).Format("2006-01-02")]
+	if ok && next.logged {
+		if v, broken := c.checkRest(date, day, next); broken {
+			violations = append(violations, v)
+		}
+	}
+
+	return violations
+}
+
+func (c ComplianceRules) checkRest(date string, previous workDay, next workDay) (Violation, bool) {
+	if c.DailyRest == 0 || previous.end.IsZero() || next.start.IsZero() {
+		return Violation{}, false
+	}
+	rest := next.start.Sub(previous.end)
+	if rest >= c.DailyRest {
+		return Violation{}, false
+	}
+
+	return Violation{date, ComplianceDailyRest, fmt.Sprintf("only %s between %s and %s, %s requires %s", formatDuration(rest),
+		previous.end.In(location).Format("2006-01-02 15:04"), next.start.In(location).Format("2006-01-02 15:04"), c.Country, formatDuration(c.DailyRest))}, true
+}
+
+// checkWeek checks the time worked in the date's ISO week
+func (c ComplianceRules) checkWeek(date string, timeline map[string]workDay) (Violation, bool) {
+	t, err := time.Parse("2006-01-02", date)
+	if c.WeeklyMax == 0 || err != nil {
+		return Violation{}, false
+	}
+
+	monday := startOfWeek(t)
+	var worked time.Duration
+	for d := monday; d.Before(monday.AddDate(0, 0, 7)); d = d.AddDate(0, 0, 1) {
+		worked += timeline[d.Format("2006-01-02")].worked
+	}
+	if worked <= c.WeeklyMax {
+		return Violation{}, false
+	}
+
+	year, week := t.ISOWeek()
+	return Violation{date, ComplianceWeeklyMax, fmt.Sprintf("%s worked in %d-W%02d, %s allows %s", formatDuration(worked), year, week, c.Country, formatDuration(c.WeeklyMax))}, true
+}
+
+// requiredBreak returns the break required for the time worked
+func (c ComplianceRules) requiredBreak(worked time.Duration) time.Duration {
+	var required time.Duration
+	for _, rule := range c.Breaks {
+		if worked > rule.After {
+			required = max(required, rule.Length)
+		}
+	}
+
+	return required
+}
+
+// enforce checks the generated day against the timeline and adds it to the timeline if it's compliant. Days
+// starting too early after the previous day are moved later, and days without the required break get one inserted,
+// as long as they still fit into the same day
+func (c ComplianceRules) enforce(date string, day []Entry, timeline map[string]workDay) ([]Entry, []Violation) {
+	wd, err := newWorkDay(day)
+	if err != nil {
+		return nil, []Violation{{date, "entries", err.Error()}}
+	}
+
+	t, _ := time.Parse("2006-01-02", date)
+	if previous, ok := timeline[t.AddDate(0, 0, -1).Format("2006-01-02")]; ok && c.DailyRest > 0 && !previous.end.IsZero() {
+		if offset := previous.end.Add(c.DailyRest).Sub(wd.start); offset > 0 {
+			// round up to whole minutes
+			offset = (offset + time.Minute - 1).Truncate(time.Minute)
+			if shifted, err := shiftDay(day, "+"+offset.String()); err == nil {
+				day = shifted
+				wd, _ = newWorkDay(day)
+			}
+		}
+	}
+
+	if missing := c.requiredBreak(wd.worked) - wd.breaks; missing > 0 {
+		if withBreak, err := insertBreak(day, max(missing, minBreakGap)); err == nil {
+			day = withBreak
+			wd, _ = newWorkDay(day)
+		}
+	}
+
+	timeline[date] = wd
+	violations := c.checkDay(date, timeline)
+	if v, broken := c.checkWeek(date, timeline); broken {
+		violations = append(violations, v)
+	}
+	if len(violations) > 0 {
+		delete(timeline, date)
+		return nil, violations
+	}
+
+	return day, nil
+}
+
+// checkChanged checks the day changed by the user against the timeline without adjusting it. Unlike checkDay, rest
+// before the following day is checked even when the following day isn't logged yet
+func (c ComplianceRules) checkChanged(date string, day []Entry, timeline map[string]workDay) []Violation {
+	wd, err := newWorkDay(day)
+	if err != nil {
+		return []Violation{{date, "entries", err.Error()}}
+	}
+	timeline[date] = wd
+	defer delete(timeline, date)
+
+	violations := c.checkDay(date, timeline)
+	t, _ := time.Parse("2006-01-02", date)
+	if next, ok := timeline[t.AddDate(0, 0, 1).Format("2006-01-02")]; ok && !next.logged {
+		if v, broken := c.checkRest(date, wd, next); broken {
+			violations = append(violations, v)
+		}
+	}
+	if v, broken := c.checkWeek(date, timeline); broken {
+		violations = append(violations, v)
+	}
+
+	return violations
+}
+
+// insertBreak inserts a break of the length in the middle of the worked time and moves the rest of the day later.
+// The break goes between entries closest to the middle, the only entry of the day is split
+func insertBreak(day []Entry, length time.Duration) ([]Entry, error) {
+	sorted := slices.Clone(day)
+	slices.SortFunc(sorted, func(a, b Entry) int { return strings.Compare(a.Start, b.Start) })
+
+	var worked time.Duration
+	for _, entry := range sorted {
+		s, e, err := entryInterval(entry)
+		if err != nil {
+			return nil, err
+		}
+		worked += e.Sub(s)
+	}
+
+	// index of the first entry after the break, and where the first entry is split when the day has one entry
+	index, split := 1, time.Time{}
+	if len(sorted) == 1 {
+		s, _, _ := entryInterval(sorted[0])
+		split = s.Add(worked / 2).Truncate(time.Minute)
+	} else {
+		var elapsed time.Duration
+		best := time.Duration(-1)
+		for i, entry := range sorted[:len(sorted)-1] {
+			s, e, _ := entryInterval(entry)
+			elapsed += e.Sub(s)
+			if distance := (elapsed - worked/2).Abs(); best < 0 || distance < best {
+				best, index = distance, i+1
+			}
+		}
+	}
+
+	withBreak := make([]Entry, 0, len(sorted)+1)
+	for i, entry := range sorted {
+		s, e, _ := entryInterval(entry)
+		switch {
+		case !split.IsZero():
+			withBreak = append(withBreak, withInterval(entry, s, split), withInterval(entry, split.Add(length), e.Add(length)))
+		case i < index:
+			withBreak = append(withBreak, entry)
+		default:
+			withBreak = append(withBreak, withInterval(entry, s.Add(length), e.Add(length)))
+		}
+	}
+
+	return withBreak, validateDay(withBreak)
+}
+
+// formatDuration formats duration without zero units eg. 7h30m
+func formatDuration(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return cmp.Or(s, "0m")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResolveCompliance(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		config  ComplianceConfig
+		want    ComplianceRules
+		wantErr bool
+	}{
+		{
+			"SlovenianDefaults",
+			"si",
+			ComplianceConfig{},
+			ComplianceRules{Country: "SI", DailyRest: 12 * time.Hour, WeeklyMax: 48 * time.Hour, Breaks: []BreakRule{{After: 4 * time.Hour, Length: 30 * time.Minute}}},
+			false,
+		},
+		{
+			"Overrides",
+			"DE",
+			ComplianceConfig{WeeklyMax: "60h", Breaks: []BreakConfig{{After: "6h", Length: "15m"}}},
+			ComplianceRules{Country: "DE", DailyRest: 11 * time.Hour, WeeklyMax: 60 * time.Hour, Breaks: []BreakRule{{After: 6 * time.Hour, Length: 15 * time.Minute}}},
+			false,
+		},
+		{"Disabled", "none", ComplianceConfig{}, ComplianceRules{Country: "none"}, false},
+		{"UnknownCountry", "XX", ComplianceConfig{}, ComplianceRules{}, true},
+		{"InvalidDuration", "SI", ComplianceConfig{DailyRest: "12 hours"}, ComplianceRules{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveCompliance(test.country, test.config)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("resolveCompliance() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if !test.wantErr && !reflect.DeepEqual(test.want, got) {
+				t.Errorf("resolveCompliance() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestComplianceCheckDay(t *testing.T) {
+	loc := useLjubljana(t)
+	rules, _ := resolveCompliance("SI", ComplianceConfig{})
+	at := func(date string, clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+		return t
+	}
+	day := func(date string, blocks ...string) workDay {
+		var entries []Entry
+		for i := 0; i < len(blocks); i += 2 {
+			entries = append(entries, Entry{Date: date, Start: blocks[i], End: blocks[i+1]})
+		}
+		wd, err := newWorkDay(entries)
+		if err != nil {
+			t.Fatalf("newWorkDay() error = %v", err)
+		}
+		return wd
+	}
+
+	tests := []struct {
+		name     string
+		timeline map[string]workDay
+		want     []string
+	}{
+		{
+			"LoggedLunchIsWork",
+			map[string]workDay{"2025-03-14": day("2025-03-14", "08:00", "11:50", "11:50", "12:20", "12:20", "16:10")},
+			[]string{ComplianceBreaks},
+		},
+		{
+			"BreakBetweenEntries",
+			map[string]workDay{"2025-03-14": day("2025-03-14", "08:00", "11:50", "12:20", "16:10")},
+			nil,
+		},
+		{
+			"MissingBreak",
+			map[string]workDay{"2025-03-14": day("2025-03-14", "08:00", "12:00", "12:10", "14:00")},
+			[]string{ComplianceBreaks},
+		},
+		{
+			"ShortDayWithoutBreak",
+			map[string]workDay{"2025-03-14": day("2025-03-14", "08:00", "12:00")},
+			nil,
+		},
+		{
+			"RestAfterLoggedDay",
+			map[string]workDay{
+				"2025-03-13": {start: at("2025-03-13", "14:00"), end: at("2025-03-13", "22:00"), worked: 8 * time.Hour, logged: true},
+				"2025-03-14": day("2025-03-14", "07:00", "11:00"),
+			},
+			[]string{ComplianceDailyRest},
+		},
+		{
+			"RestBeforeLoggedDay",
+			map[string]workDay{
+				"2025-03-14": day("2025-03-14", "15:00", "19:00", "19:30", "23:00"),
+				"2025-03-15": {start: at("2025-03-15", "08:00"), end: at("2025-03-15", "12:00"), worked: 4 * time.Hour, logged: true},
+			},
+			[]string{ComplianceDailyRest},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, v := range rules.checkDay("2025-03-14", test.timeline) {
+				got = append(got, v.Rule)
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("checkDay() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestComplianceCheckWeek(t *testing.T) {
+	rules, _ := resolveCompliance("SI", ComplianceConfig{})
+	timeline := map[string]workDay{
+		"2025-03-09": {worked: 10 * time.Hour},
+		"2025-03-10": {worked: 10 * time.Hour},
+		"2025-03-11": {worked: 10 * time.Hour},
+		"2025-03-12": {worked: 10 * time.Hour},
+		"2025-03-13": {worked: 10 * time.Hour},
+	}
+
+	if _, broken := rules.checkWeek("2025-03-13", timeline); broken {
+		t.Errorf("checkWeek() should allow 40h in 2025-W11")
+	}
+
+	timeline["2025-03-14"] = workDay{worked: 9 * time.Hour}
+	v, broken := rules.checkWeek("2025-03-14", timeline)
+	if !broken || v.Detail != "49h worked in 2025-W11, SI allows 48h" {
+		t.Errorf("checkWeek() = %q, %v, want weekly max violation", v.Detail, broken)
+	}
+}
+
+func TestComplianceEnforce(t *testing.T) {
+	loc := useLjubljana(t)
+	rules, _ := resolveCompliance("SI", ComplianceConfig{})
+	late, _ := time.ParseInLocation("2006-01-02 15:04", "2025-03-13 20:30", loc)
+	timeline := map[string]workDay{"2025-03-13": {end: late, worked: 8 * time.Hour, logged: true}}
+
+	// templated day with its lunch break
+	day := []Entry{
+		{Date: "2025-03-14", Start: "08:00", End: "11:50"},
+		{Date: "2025-03-14", Start: "12:20", End: "16:10"},
+	}
+	// moved after the daily rest, the lunch break is enough so no other break is inserted
+	want := []Entry{
+		{Date: "2025-03-14", Start: "08:30", End: "12:20"},
+		{Date: "2025-03-14", Start: "12:50", End: "16:40"},
+	}
+
+	got, violations := rules.enforce("2025-03-14", day, timeline)
+
+	if len(violations) > 0 {
+		t.Fatalf("enforce() violations = %v", violations)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("enforce() = %v, want %v", got, want)
+	}
+	if _, ok := timeline["2025-03-14"]; !ok {
+		t.Errorf("enforce() should add compliant day to the timeline")
+	}
+
+	// the only entry of the day is split by the break
+	got, violations = rules.enforce("2025-03-17", []Entry{{Date: "2025-03-17", Start: "08:00", End: "16:00", ProjectId: 5}}, timeline)
+	want = []Entry{{Date: "2025-03-17", Start: "08:00", End: "12:00", ProjectId: 5}, {Date: "2025-03-17", Start: "12:30", End: "16:30", ProjectId: 5}}
+	if len(violations) > 0 || !reflect.DeepEqual(want, got) {
+		t.Errorf("enforce() = %v %v, want %v with inserted break", got, violations, want)
+	}
+
+	// the break can't move the day past midnight
+	_, violations = rules.enforce("2025-03-19", []Entry{{Date: "2025-03-19", Start: "15:50", End: "23:50"}}, timeline)
+	if len(violations) != 1 || violations[0].Rule != ComplianceBreaks {
+		t.Errorf("enforce() violations = %v, want missing break", violations)
+	}
+	if _, ok := timeline["2025-03-19"]; ok {
+		t.Errorf("enforce() should not add non-compliant day to the timeline")
+	}
+}
+
+func TestInsertBreak(t *testing.T) {
+	useLjubljana(t)
+	tests := []struct {
+		name string
+		day  []Entry
+		want []Entry
+	}{
+		{
+			"ShortGit",
+			[]Entry{{Date: "2025-03-14", Start: "09:00", End: "14:00"}},
+			[]Entry{{Date: "2025-03-14", Start: "09:00", End: "11:30"}, {Date: "2025-03-14", Start: "12:00", End: "14:30"}},
+		},
+		{
+			"BetweenProjects",
+			[]Entry{
+				{Date: "2025-03-14", Start: "08:00", End: "10:00", ProjectId: 5},
+				{Date: "2025-03-14", Start: "10:00", End: "13:00", ProjectId: 7},
+				{Date: "2025-03-14", Start: "13:00", End: "16:00", ProjectId: 9},
+			},
+			[]Entry{
+				{Date: "2025-03-14", Start: "08:00", End: "10:00", ProjectId: 5},
+				{Date: "2025-03-14", Start: "10:00", End: "13:00", ProjectId: 7},
+				{Date: "2025-03-14", Start: "13:30", End: "16:30", ProjectId: 9},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := insertBreak(test.day, 30*time.Minute)
+			if err != nil {
+				t.Fatalf("insertBreak() error = %v", err)
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("insertBreak() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                             "0m",
+		30 * time.Minute:              "30m",
+		12 * time.Hour:                "12h",
+		7*time.Hour + 30*time.Minute:  "7h30m",
+		9*time.Hour + 29*time.Second:  "9h",
+		10*time.Hour + 90*time.Second: "10h2m",
+	}
+
+	for d, want := range tests {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -11,8 +11,60 @@ import (
 var configFile embed.FS
 
 type Config struct {
-	ApiToken   string `json:"apiToken"`
-	EmployeeId int    `json:"employeeId"`
+	ApiToken   string   `json:"apiToken"`
+	EmployeeId int      `json:"employeeId"`
+	Timezone   string   `json:"timezone"`
+	ProjectId  int      `json:"projectId"`
+	TaskId     int      `json:"taskId"`
+	Note       string   `json:"note"`
+	Schedule   Schedule `json:"schedule"`
+	// BaseUrl points the app to another BambooHR API eg. the fake server
+	BaseUrl string `json:"baseUrl"`
+	// Compliance overrides labor-law rules of the country
+	Compliance ComplianceConfig `json:"compliance"`
+}
+
+// Schedule configures how work entries are generated
+type Schedule struct {
+	// Projects split each work day by their weights, eg. 60% Project A, 30% Project B and 10% internal
+	Projects []ProjectAllocation `json:"projects"`
+	// Git derives work blocks from commit history instead of the randomized template
+	Git GitSchedule `json:"git"`
+	// Ics derives work blocks from busy events in iCalendar file instead of the randomized template
+	Ics IcsSchedule `json:"ics"`
+}
+
+type GitSchedule struct {
+	Repos  []string `json:"repos"`
+	Author string   `json:"author"`
+	// Padding is added before the first and after the last commit of the day eg. 30m
+	Padding string `json:"padding"`
+}
+
+type IcsSchedule struct {
+	File string `json:"file"`
+	// Notes copies titles of events into entry notes
+	Notes bool `json:"notes"`
+}
+
+// ComplianceConfig selects the country's labor-law rules, durations eg. 11h override its defaults
+type ComplianceConfig struct {
+	Country   string        `json:"country"`
+	DailyRest string        `json:"dailyRest"`
+	WeeklyMax string        `json:"weeklyMax"`
+	Breaks    []BreakConfig `json:"breaks"`
+}
+
+type BreakConfig struct {
+	After  string `json:"after"`
+	Length string `json:"length"`
+}
+
+type ProjectAllocation struct {
+	ProjectId int     `json:"projectId"`
+	TaskId    int     `json:"taskId"`
+	Note      string  `json:"note"`
+	Weight    float64 `json:"weight"`
 }
 
 func loadConfig(filename string) (*Config, error) {
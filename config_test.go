@@ -21,6 +21,28 @@ func TestReadConfigFile(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"ConfigWithTimezone",
+			[]byte(`{"apiToken":"myApiToken","employeeId":1234,"timezone":"Europe/Ljubljana"}`),
+			Config{
+				ApiToken:   "myApiToken",
+				EmployeeId: 1234,
+				Timezone:   "Europe/Ljubljana",
+			},
+			false,
+		},
+		{
+			"ConfigWithProject",
+			[]byte(`{"apiToken":"myApiToken","employeeId":1234,"projectId":5,"taskId":12,"note":"Development"}`),
+			Config{
+				ApiToken:   "myApiToken",
+				EmployeeId: 1234,
+				ProjectId:  5,
+				TaskId:     12,
+				Note:       "Development",
+			},
+			false,
+		},
 		{
 			"EmptyConfig",
 			[]byte(`{"apiToken":"","employeeId":0}`),
@@ -43,7 +65,7 @@ func TestReadConfigFile(t *testing.T) {
 			config, err := readConfigFile(test.input)
 
 			if (err != nil) != test.wantErr {
-				t.Errorf(`readConfigFile(bytes) should return an error", got %q`, config)
+				t.Errorf(`readConfigFile(bytes) should return an error", got %v`, config)
 				return
 			}
 			if !reflect.DeepEqual(*config, test.want) {
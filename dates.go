@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DateToday     = "today"
+	DateYesterday = "yesterday"
+	ThisMonth     = "this-month"
+	LastMonth     = "last-month"
+	ThisWeek      = "this-week"
+	LastWeek      = "last-week"
+)
+
+// clock returns the current time - tests can replace it to pin the current date
+var clock = time.Now
+
+// today returns current date at midnight UTC, so it can be compared with dates parsed from flags
+func today() time.Time {
+	now := clock()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseDate parses YYYY-MM-DD date or one of the 'today' and 'yesterday' shorthands
+func parseDate(value string) (time.Time, error) {
+	switch strings.ToLower(value) {
+	case DateToday:
+		return today(), nil
+	case DateYesterday:
+		return today().AddDate(0, 0, -1), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New(fmt.Sprintf("unable to parse date '%s', expected YYYY-MM-DD, %s or %s", value, DateToday, DateYesterday))
+	}
+
+	return date, nil
+}
+
+// parseMonth parses YYYY-MM month or one of the 'this-month' and 'last-month' shorthands and returns
+// the first day of the month and the first day of the following month
+func parseMonth(value string) (time.Time, time.Time, error) {
+	t := today()
+	thisMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch strings.ToLower(value) {
+	case ThisMonth:
+		start = thisMonth
+	case LastMonth:
+		start = thisMonth.AddDate(0, -1, 0)
+	default:
+		var err error
+		start, err = time.Parse("2006-01", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("unable to parse month '%s', expected YYYY-MM, %s or %s", value, ThisMonth, LastMonth))
+		}
+	}
+
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// parseWeek parses ISO YYYY-Www week or one of the 'this-week' and 'last-week' shorthands and returns
+// the Monday of the week and the Monday of the following week
+func parseWeek(value string) (time.Time, time.Time, error) {
+	var start time.Time
+	switch strings.ToLower(value) {
+	case ThisWeek:
+		start = startOfWeek(today())
+	case LastWeek:
+		start = startOfWeek(today()).AddDate(0, 0, -7)
+	default:
+		invalidErr := errors.New(fmt.Sprintf("unable to parse week '%s', expected YYYY-Www, %s or %s", value, ThisWeek, LastWeek))
+
+		yearStr, weekStr, ok := strings.Cut(strings.ToUpper(value), "-W")
+		if !ok {
+			return time.Time{}, time.Time{}, invalidErr
+		}
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, invalidErr
+		}
+		week, err := strconv.Atoi(weekStr)
+		if err != nil || week < 1 || week > 53 {
+			return time.Time{}, time.Time{}, invalidErr
+		}
+
+		// January 4th is always in the first ISO week of the year
+		start = startOfWeek(time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)).AddDate(0, 0, (week-1)*7)
+		if y, _ := start.ISOWeek(); y != year {
+			return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("year %d does not have week %d", year, week))
+		}
+	}
+
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+// startOfWeek returns Monday of the week the date belongs to
+func startOfWeek(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// resolveDateRange converts 'start'/'end', 'month' or 'week' flags into a date range, where the end date
+// is exclusive. When inclusive is set, the provided 'end' date is included in the range as well
+func resolveDateRange(startDate string, endDate string, month string, week string, inclusive bool) (time.Time, time.Time, error) {
+	if month != "" && week != "" {
+		return time.Time{}, time.Time{}, errors.New("'month' and 'week' cannot be used together")
+	}
+	if (month != "" || week != "") && (startDate != "" || endDate != "") {
+		return time.Time{}, time.Time{}, errors.New("'month' and 'week' cannot be combined with 'start' and 'end' dates")
+	}
+
+	if month != "" {
+		return parseMonth(month)
+	}
+	if week != "" {
+		return parseWeek(week)
+	}
+
+	if startDate == "" {
+		return time.Time{}, time.Time{}, errors.New("invalid 'start' date filter provided")
+	}
+	if endDate == "" {
+		return time.Time{}, time.Time{}, errors.New("invalid 'end' date filter provided")
+	}
+	start, err := parseDate(startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("invalid 'start' date - %v", err))
+	}
+	end, err := parseDate(endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("invalid 'end' date - %v", err))
+	}
+	if inclusive {
+		end = end.AddDate(0, 0, 1)
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("'end' date cannot be before 'start' date")
+	}
+
+	return start, end, nil
+}
+
+// inclusiveEnd returns the last date of the range with exclusive end date, unparseable dates are returned as they are
+func inclusiveEnd(endDate string) string {
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return endDate
+	}
+
+	return end.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// resolveRequiredRange converts year or date range into a date range, where end date is exclusive
+func resolveRequiredRange(year int, startDate string, endDate string, month string, week string, inclusive bool) (time.Time, time.Time, error) {
+	hasRange := month != "" || week != "" || startDate != "" || endDate != ""
+	if year != 0 && hasRange {
+		return time.Time{}, time.Time{}, errors.New("'year' cannot be combined with 'month', 'week' or 'start' and 'end' dates")
+	}
+	if year != 0 {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0), nil
+	}
+	if !hasRange {
+		return time.Time{}, time.Time{}, errors.New("invalid 'year', 'month', 'week' or 'start'/'end' provided")
+	}
+
+	return resolveDateRange(startDate, endDate, month, week, inclusive)
+}
+
+// resolveLocation returns employee's time zone - the configured one takes precedence, otherwise the time zone
+// of the latest entry tracked in BambooHR is used, falling back to the system time zone
+func resolveLocation(name string, entries []TimeEntry) (*time.Location, error) {
+	if name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unknown time zone '%s': %v", name, err))
+		}
+		return loc, nil
+	}
+
+	var latest *TimeEntry
+	for i, entry := range entries {
+		if entry.Timezone == "" {
+			continue
+		}
+		if latest == nil || entry.Start.After(latest.Start) {
+			latest = &entries[i]
+		}
+	}
+	if latest != nil {
+		loc, err := time.LoadLocation(latest.Timezone)
+		if err == nil {
+			return loc, nil
+		}
+	}
+
+	return time.Local, nil
+}
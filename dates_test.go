@@ -0,0 +1,145 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func pinClock(t *testing.T, date string) {
+	t.Helper()
+	now, err := time.Parse("2006-01-02 15:04", date)
+	if err != nil {
+		t.Fatalf("unable to pin clock: %v", err)
+	}
+
+	original := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = original })
+}
+
+func TestResolveDateRange(t *testing.T) {
+	type args struct {
+		startDate string
+		endDate   string
+		month     string
+		week      string
+		inclusive bool
+	}
+
+	tests := []struct {
+		name      string
+		input     args
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"ExplicitDates", args{"2025-03-01", "2025-04-01", "", "", false}, "2025-03-01", "2025-04-01", false},
+		{"InclusiveEnd", args{"2025-03-01", "2025-03-31", "", "", true}, "2025-03-01", "2025-04-01", false},
+		{"Today", args{"yesterday", "today", "", "", true}, "2025-03-13", "2025-03-15", false},
+		{"Month", args{"", "", "2025-02", "", false}, "2025-02-01", "2025-03-01", false},
+		{"ThisMonth", args{"", "", "this-month", "", false}, "2025-03-01", "2025-04-01", false},
+		{"LastMonth", args{"", "", "last-month", "", false}, "2025-02-01", "2025-03-01", false},
+		{"Week", args{"", "", "", "2025-W12", false}, "2025-03-17", "2025-03-24", false},
+		{"FirstWeekInPreviousYear", args{"", "", "", "2025-W01", false}, "2024-12-30", "2025-01-06", false},
+		{"ThisWeek", args{"", "", "", "this-week", false}, "2025-03-10", "2025-03-17", false},
+		{"LastWeek", args{"", "", "", "last-week", false}, "2025-03-03", "2025-03-10", false},
+		{"InvalidWeek", args{"", "", "", "2025-W54", false}, "", "", true},
+		{"MissingWeek", args{"", "", "", "2025-W53", false}, "", "", true},
+		{"InvalidMonth", args{"", "", "2025-13", "", false}, "", "", true},
+		{"MonthAndDates", args{"2025-03-01", "", "2025-03", "", false}, "", "", true},
+		{"MonthAndWeek", args{"", "", "2025-03", "2025-W12", false}, "", "", true},
+		{"MissingEnd", args{"2025-03-01", "", "", "", false}, "", "", true},
+		{"EndBeforeStart", args{"today", "yesterday", "", "", false}, "", "", true},
+	}
+
+	// Friday
+	pinClock(t, "2025-03-14 10:30")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, err := resolveDateRange(test.input.startDate, test.input.endDate, test.input.month, test.input.week, test.input.inclusive)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("resolveDateRange() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if test.wantErr {
+				return
+			}
+			if start.Format("2006-01-02") != test.wantStart || end.Format("2006-01-02") != test.wantEnd {
+				t.Errorf("resolveDateRange() = %s - %s, want %s - %s", start.Format("2006-01-02"), end.Format("2006-01-02"), test.wantStart, test.wantEnd)
+			}
+		})
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	entries := []TimeEntry{
+		{Timezone: "America/New_York", Start: time.Date(2025, time.March, 13, 9, 0, 0, 0, time.UTC)},
+		{Timezone: "Europe/Ljubljana", Start: time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		entries []TimeEntry
+		want    string
+		wantErr bool
+	}{
+		{"Configured", "Asia/Tokyo", entries, "Asia/Tokyo", false},
+		{"LatestEntry", "", entries, "Europe/Ljubljana", false},
+		{"SystemFallback", "", nil, time.Local.String(), false},
+		{"Unknown", "Mars/Olympus", entries, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveLocation(test.config, test.entries)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("resolveLocation() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if err == nil && got.String() != test.want {
+				t.Errorf("resolveLocation() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveRequiredRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		year      int
+		startDate string
+		endDate   string
+		month     string
+		week      string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"Year", 2025, "", "", "", "", "2025-01-01", "2026-01-01", false},
+		{"Month", 0, "", "", "2025-03", "", "2025-03-01", "2025-04-01", false},
+		{"Range", 0, "2025-03-01", "2025-03-15", "", "", "2025-03-01", "2025-03-15", false},
+		{"YearAndMonth", 2025, "", "", "2025-03", "", "", "", true},
+		{"YearAndWeek", 2025, "", "", "", "2025-W10", "", "", true},
+		{"YearAndStart", 2025, "2025-03-01", "", "", "", "", "", true},
+		{"Missing", 0, "", "", "", "", "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, err := resolveRequiredRange(test.year, test.startDate, test.endDate, test.month, test.week, false)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("resolveRequiredRange() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if err == nil && (start.Format("2006-01-02") != test.wantStart || end.Format("2006-01-02") != test.wantEnd) {
+				t.Errorf("resolveRequiredRange() = %s - %s, want %s - %s", start.Format("2006-01-02"), end.Format("2006-01-02"), test.wantStart, test.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mluksic/bamboo/fakebamboo"
+)
+
+// TestMain runs the app instead of the tests, when the test binary is started by runApp
+func TestMain(m *testing.M) {
+	if os.Getenv("BAMBOO_E2E") == "1" {
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// startFakeBamboo starts fake BambooHR, where employee 12 logged first two days of 2025-W11 and took a day off,
+// while employee 34 logged the whole week. Monday of 2025-W12 is a company holiday
+func startFakeBamboo(t *testing.T) (*fakebamboo.Server, string) {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	if err != nil {
+		t.Fatalf("unable to load location: %v", err)
+	}
+	at := func(date string, clock string) *time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+		return &t
+	}
+
+	seed := fakebamboo.Seed{
+		ApiKey:   "key",
+		Timezone: "Europe/Ljubljana",
+		TimeOff: []fakebamboo.TimeOff{
+			{Id: 1, Type: "Vacation", EmployeeId: 12, Start: "2025-03-12", End: "2025-03-12"},
+			{Id: 2, Type: "holiday", Name: "Company Day", Start: "2025-03-17", End: "2025-03-17"},
+		},
+		Projects: []fakebamboo.Project{{Id: 5, Name: "Development"}},
+	}
+	for _, date := range []string{"2025-03-10", "2025-03-11"} {
+		seed.Entries = append(seed.Entries, fakebamboo.TimeEntry{EmployeeId: 12, Date: date, Start: at(date, "08:00"), End: at(date, "16:00")})
+	}
+	for _, date := range []string{"2025-03-10", "2025-03-11", "2025-03-12", "2025-03-13", "2025-03-14"} {
+		seed.Entries = append(seed.Entries, fakebamboo.TimeEntry{EmployeeId: 34, Date: date, Start: at(date, "09:00"), End: at(date, "17:00")})
+	}
+
+	fake, err := fakebamboo.New(seed)
+	if err != nil {
+		t.Fatalf("fakebamboo.New() error = %v", err)
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, server.URL + fakebamboo.BasePath
+}
+
+// runApp runs the command with the arguments against the fake BambooHR, and returns its output and exit code
+func runApp(t *testing.T, baseUrl string, command string, args ...string) (string, int) {
+	t.Helper()
+	dir := t.TempDir()
+	args = slices.Concat([]string{command}, args)
+	// commands without BambooHR flags eg. help are run without base URL
+	if baseUrl != "" {
+		args = append(args, "--baseUrl", baseUrl, "--cacheTtl", "0")
+	}
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "BAMBOO_E2E=1", "HOME="+dir, "XDG_CONFIG_HOME="+dir, "XDG_CACHE_HOME="+dir)
+
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(output), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("unable to run the app: %v", err)
+	}
+
+	return string(output), 0
+}
+
+func TestE2EList(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "list", "--apiKey", "key", "--employeeId", "12", "--week", "2025-W11", "--timezone", "Europe/Ljubljana")
+
+	if code != 0 {
+		t.Fatalf("list exited with %d: %s", code, output)
+	}
+	for _, want := range []string{"2025-03-10", "08:00", "time off - Vacation", "weekend - Saturday", "Missing workdays: 2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("list output doesn't contain %q: %s", want, output)
+		}
+	}
+}
+
+func TestE2EListCompanyHoliday(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "list", "--apiKey", "key", "--employeeId", "12", "--week", "2025-W12", "--timezone", "Europe/Ljubljana")
+
+	if code != 0 {
+		t.Fatalf("list exited with %d: %s", code, output)
+	}
+	for _, want := range []string{"Company Day", "Missing workdays: 4"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("list output doesn't contain %q: %s", want, output)
+		}
+	}
+}
+
+func TestE2EListExcludesEndDate(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	// employee 34 logged 2025-03-14 too, the end date isn't part of the range
+	output, code := runApp(t, baseUrl, "list", "--apiKey", "key", "--employeeId", "34", "--start", "2025-03-10", "--end", "2025-03-14", "--timezone", "Europe/Ljubljana")
+
+	if code != 0 {
+		t.Fatalf("list exited with %d: %s", code, output)
+	}
+	if !strings.Contains(output, "total working hours: 32 hours and 0 minutes") || strings.Contains(output, "2025-03-14") {
+		t.Errorf("list output should only count hours before the end date: %s", output)
+	}
+
+	output, code = runApp(t, baseUrl, "balance", "--apiKey", "key", "--employeeId", "34", "--start", "2025-03-10", "--end", "2025-03-14", "--timezone", "Europe/Ljubljana")
+	if code != 0 || strings.Contains(output, "40h") || strings.Contains(output, "+8h") {
+		t.Errorf("balance = %d %s, want 32h tracked against 32h required", code, output)
+	}
+}
+
+func TestE2EAdd(t *testing.T) {
+	fake, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "add", "--apiKey", "key", "--employeeId", "12", "--week", "2025-W11", "--timezone", "Europe/Ljubljana", "--projectId", "5", "--force")
+
+	if code != 0 {
+		t.Fatalf("add exited with %d: %s", code, output)
+	}
+	var dates []string
+	for _, entry := range fake.Entries() {
+		if entry.EmployeeId == 12 && !slices.Contains(dates, entry.Date) {
+			dates = append(dates, entry.Date)
+		}
+		if entry.EmployeeId == 12 && entry.Date >= "2025-03-13" && (entry.ProjectInfo == nil || entry.ProjectInfo.Project.Name != "Development") {
+			t.Errorf("generated entry %v should be assigned to the project", entry)
+		}
+	}
+	if want := []string{"2025-03-10", "2025-03-11", "2025-03-13", "2025-03-14"}; !slices.Equal(want, dates) {
+		t.Errorf("logged dates after add = %v, want %v", dates, want)
+	}
+}
+
+func TestE2ETeamBalance(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "balance", "--apiKey", "key", "--employeeIds", "12,34", "--week", "2025-W11", "--timezone", "Europe/Ljubljana")
+
+	if code != 0 {
+		t.Fatalf("balance exited with %d: %s", code, output)
+	}
+	for _, want := range []string{"16h", "32h", "-16h", "40h", "+0m", "12 is missing: 2025-03-13, 2025-03-14"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("balance output doesn't contain %q: %s", want, output)
+		}
+	}
+}
+
+func TestE2ECheck(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "check", "--apiKey", "key", "--employeeId", "12", "--days", "3", "--timezone", "Europe/Ljubljana")
+
+	if code != ExitMissing || !strings.Contains(output, "missing 3 workdays") {
+		t.Errorf("check = %d %s, want exit code %d and 3 missing workdays", code, output, ExitMissing)
+	}
+}
+
+func TestE2EProjects(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "projects", "--apiKey", "key", "--employeeId", "12")
+
+	if code != 0 || !strings.Contains(output, "Development") {
+		t.Errorf("projects = %d %s, want seeded project", code, output)
+	}
+}
+
+func TestE2EInvalidApiKey(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "list", "--apiKey", "invalid", "--employeeId", "12", "--week", "2025-W11")
+
+	if code != 1 || !strings.Contains(output, "401") {
+		t.Errorf("list with invalid API key = %d %s, want exit code 1 and 401 error", code, output)
+	}
+}
+
+func TestApiUrl(t *testing.T) {
+	originalBaseUrl, originalApiKey := baseUrl, apiKey
+	baseUrl, apiKey = "http://127.0.0.1:8081/api/gateway.php/flaviar/v1/", "key/with:special"
+	t.Cleanup(func() { baseUrl, apiKey = originalBaseUrl, originalApiKey })
+
+	got := apiUrl("/time_off/whos_out?start=%s&end=%s", "2025-03-01", "2025-04-01")
+
+	want := "http://key%2Fwith%3Aspecial:x@127.0.0.1:8081/api/gateway.php/flaviar/v1/time_off/whos_out?start=2025-03-01&end=2025-04-01"
+	if got != want {
+		t.Errorf("apiUrl() = %q, want %q", got, want)
+	}
+	for _, invalid := range []string{"api.bamboohr.com", "ftp://api.bamboohr.com", "https://"} {
+		if err := validateBaseUrl(invalid); err == nil {
+			t.Errorf("validateBaseUrl(%q) should return error", invalid)
+		}
+	}
+}
+
+func TestE2EUnknownFlag(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+
+	output, code := runApp(t, baseUrl, "projects", "--apiKey", "key", "--employeeId", "12", "--month", "last-month")
+
+	if code != 1 || !strings.Contains(output, "-month") {
+		t.Errorf("projects with unknown flag = %d %s, want exit code 1 and rejected flag", code, output)
+	}
+}
+
+func TestE2EHelp(t *testing.T) {
+	output, code := runApp(t, "", "help", "check")
+
+	if code != 0 || !strings.Contains(output, "Usage: bamboo check [flags]") || !strings.Contains(output, "-webhook") {
+		t.Errorf("help check = %d %s, want usage and flags of check", code, output)
+	}
+}
+
+func TestE2EExportUnsupportedFormat(t *testing.T) {
+	_, baseUrl := startFakeBamboo(t)
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0o600); err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	output, code := runApp(t, baseUrl, "export", "--apiKey", "key", "--employeeId", "12", "--week", "2025-W11", path)
+
+	content, _ := os.ReadFile(path)
+	if code != 1 || string(content) != "keep me" {
+		t.Errorf("export to .txt = %d %s, file %q, want exit code 1 and untouched file", code, output, content)
+	}
+}
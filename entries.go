@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -26,23 +28,34 @@ type Entry struct {
 	Date       string `json:"date"`
 	Start      string `json:"start"`
 	End        string `json:"end"`
+	ProjectId  int    `json:"projectId,omitempty"`
+	TaskId     int    `json:"taskId,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+// WorkBlock is a continuous interval of work within a single day
+type WorkBlock struct {
+	start time.Time
+	end   time.Time
+	// note overrides the default entry note eg. with titles of calendar events
+	note string
 }
 
 type TimeEntries []TimeEntry
 
 type TimeEntry struct {
-	Id          int         `json:"id"`
-	EmployeeId  int         `json:"employeeId"`
-	Type        string      `json:"type"`
-	Date        string      `json:"date"`
-	Start       time.Time   `json:"start"`
-	End         time.Time   `json:"end"`
-	Timezone    string      `json:"timezone"`
-	Hours       float64     `json:"hours"`
-	Note        interface{} `json:"note"`
-	ProjectInfo interface{} `json:"projectInfo"`
-	ApprovedAt  time.Time   `json:"approvedAt"`
-	Approved    bool        `json:"approved"`
+	Id          int          `json:"id"`
+	EmployeeId  int          `json:"employeeId"`
+	Type        string       `json:"type"`
+	Date        string       `json:"date"`
+	Start       time.Time    `json:"start"`
+	End         time.Time    `json:"end"`
+	Timezone    string       `json:"timezone"`
+	Hours       float64      `json:"hours"`
+	Note        string       `json:"note"`
+	ProjectInfo *ProjectInfo `json:"projectInfo"`
+	ApprovedAt  time.Time    `json:"approvedAt"`
+	Approved    bool         `json:"approved"`
 }
 
 type Report struct {
@@ -51,10 +64,15 @@ type Report struct {
 }
 type DayReport struct {
 	workHours float64
+	// first clock in and last clock out of the day
+	start time.Time
+	end   time.Time
 }
 type YearReport struct {
 	month map[string]MonthReport
 }
+
+// MonthReport holds required hours for a single period - week, month or quarter
 type MonthReport struct {
 	workDays          int
 	holidays          int
@@ -63,35 +81,140 @@ type MonthReport struct {
 	totalHours        int
 }
 
-func processList(report Report) {
+const (
+	GroupByWeek    = "week"
+	GroupByMonth   = "month"
+	GroupByQuarter = "quarter"
+)
+
+var groupings = []string{GroupByWeek, GroupByMonth, GroupByQuarter}
+
+// max number of clock entries pushed to BambooHR in a single request
+const maxEntriesPerRequest = 100
+
+// processList prints every day between start and end date. Days without hours show why they're skipped by
+// the generator, or MISSING when hours should have been logged
+func processList(report Report, startDate string, endDate string) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		fmt.Printf("Unable to parse date from string: %v \n", err)
+		os.Exit(1)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		fmt.Printf("Unable to parse date from string: %v \n", err)
+		os.Exit(1)
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
 	defer w.Flush()
 	// table header
-	fmt.Fprintf(w, "Date\tWeekday\tTotal\t\n")
+	fmt.Fprintf(w, "Date\tWeekday\tStart\tEnd\tTotal\tStatus\t\n")
+
+	missing := 0
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		day, ok := report.days[date]
+		if ok {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\t\n", date, d.Weekday(), formatLocalTime(day.start), formatLocalTime(day.end), convertDecimalTimeToTime(day.workHours))
+			continue
+		}
 
-	// sort dates in asc order because map sorting order is random
-	dates := make([]string, 0, len(report.days))
-	for date := range report.days {
-		dates = append(dates, date)
+		status := "MISSING"
+		if decision := evaluateDay(d, report); decision.excluded() {
+			status = decision.reason()
+		} else {
+			missing++
+		}
+		fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t%s\t\n", date, d.Weekday(), status)
 	}
-	sort.Strings(dates)
 
-	for _, date := range dates {
-		t, err := time.Parse("2006-01-02", date)
+	fmt.Fprintf(w, "\nYour total working hours: %s \n", convertDecimalTimeToTime(report.totalWorkHours))
+	if missing > 0 {
+		fmt.Fprintf(w, "Missing workdays: %d \n", missing)
+	}
+	fmt.Fprintf(w, "Times are shown in %s time zone \n", location)
+}
+
+// formatLocalTime formats time in employee's time zone
+func formatLocalTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.In(location).Format("15:04")
+}
+
+// processDetailedList prints every entry with its type, note and approval status, followed by the day's subtotal
+func processDetailedList(entries []TimeEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	defer w.Flush()
+	// table header
+	fmt.Fprintf(w, "Date\tWeekday\tStart\tEnd\tHours\tType\tApproved\tNote\t\n")
+
+	sorted := sortTimeEntries(entries)
+
+	total, dayTotal := 0.0, 0.0
+	for i, entry := range sorted {
+		t, err := time.Parse("2006-01-02", entry.Date)
 		if err != nil {
 			fmt.Printf("Unable to parse date from string: %v \n", err)
 			os.Exit(1)
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\n", date, t.Weekday(), convertDecimalTimeToTime(report.days[date].workHours))
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", entry.Date, t.Weekday(), formatLocalTime(entry.Start), formatLocalTime(entry.End),
+			convertDecimalTimeToTime(entry.Hours), entry.Type, formatApproval(entry), entry.Note)
+		total += entry.Hours
+		dayTotal += entry.Hours
+
+		// subtotal after the day's last entry
+		if i == len(sorted)-1 || sorted[i+1].Date != entry.Date {
+			fmt.Fprintf(w, "\t\t\tSubtotal\t%s\t\t\t\t\n", convertDecimalTimeToTime(dayTotal))
+			dayTotal = 0
+		}
 	}
 
-	fmt.Fprintf(w, "\nYour total working hours: %s \n", convertDecimalTimeToTime(report.totalWorkHours))
+	fmt.Fprintf(w, "\nTotal of listed entries: %s \n", convertDecimalTimeToTime(total))
+	fmt.Fprintf(w, "Times are shown in %s time zone \n", location)
 }
 
-func addWorkingHours(report Report, force bool) {
-	var storeHoursUrlTemplate = "https://%s:x@api.bamboohr.com/api/gateway.php/flaviar/v1/time_tracking/clock_entries/store"
-	url := fmt.Sprintf(storeHoursUrlTemplate, apiKey)
+// filterEntries returns entries matching the approval and type filters, empty type matches all entries
+func filterEntries(entries []TimeEntry, unapproved bool, entryType string) []TimeEntry {
+	var filtered []TimeEntry
+	for _, entry := range entries {
+		if unapproved && entry.Approved {
+			continue
+		}
+		if entryType != "" && !strings.EqualFold(entry.Type, entryType) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
 
+// sortTimeEntries returns a copy of entries sorted by date and start time
+func sortTimeEntries(entries []TimeEntry) []TimeEntry {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b TimeEntry) int {
+		return cmp.Or(strings.Compare(a.Date, b.Date), a.Start.Compare(b.Start))
+	})
+
+	return sorted
+}
+
+func formatApproval(entry TimeEntry) string {
+	if !entry.Approved {
+		return "no"
+	}
+	if entry.ApprovedAt.IsZero() {
+		return "yes"
+	}
+
+	return "yes (" + entry.ApprovedAt.In(location).Format("2006-01-02") + ")"
+}
+
+func addWorkingHours(report Report, force bool) {
 	entries, err := generateWorkEntries(report, startDate, endDate)
 	if err != nil {
 		fmt.Printf("Unable to create post request entries: %v", err)
@@ -102,7 +225,14 @@ func addWorkingHours(report Report, force bool) {
 		fmt.Println("There are no generated entries for specified dates. Exiting the program...")
 		os.Exit(0)
 	}
-	isConfirmed, err := askForConfirmation(entries, force)
+
+	submitEntries(entries, report, force)
+}
+
+// submitEntries asks for confirmation and pushes confirmed entries to BambooHR, it returns the entries as they were
+// stored, after they were reviewed by the user
+func submitEntries(entries []Entry, report Report, force bool) []Entry {
+	entries, isConfirmed, err := askForConfirmation(entries, report, force)
 	if err != nil {
 		fmt.Printf("There was an issue asking for confirmation: %v", err)
 		os.Exit(1)
@@ -112,59 +242,125 @@ func addWorkingHours(report Report, force bool) {
 		os.Exit(0)
 	}
 
+	if offline {
+		fmt.Println("Entries aren't pushed to BambooHR in offline mode, run it again without 'offline'")
+		os.Exit(0)
+	}
 	fmt.Println("Pushing hours to BambooHR. Please wait...")
 
+	if err := storeEntries(entries); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Successfully populated working hour entries between two dates. Please double-check in Bamboo")
+
+	return entries
+}
+
+// storeEntries pushes entries to BambooHR in batches, so large imports don't hit request size limits. When a batch
+// fails, the error tells which entries were already pushed
+func storeEntries(entries []Entry) error {
+	stored := 0
+	for batch := range slices.Chunk(entries, maxEntriesPerRequest) {
+		if err := storeEntriesBatch(batch); err != nil {
+			if stored == 0 {
+				return err
+			}
+			// pushed entries are in BambooHR already, so cached timesheet is stale
+			_ = clearCache("timesheet")
+			return errors.New(fmt.Sprintf("%v - %d entries %s were pushed, %d entries %s weren't", err,
+				stored, entriesRange(entries[:stored]), len(entries)-stored, entriesRange(entries[stored:])))
+		}
+		stored += len(batch)
+	}
+
+	return clearCache("timesheet")
+}
+
+// entriesRange describes dates covered by the entries eg. 'between 2025-03-03 and 2025-03-07'
+func entriesRange(entries []Entry) string {
+	first, last := entries[0].Date, entries[0].Date
+	for _, entry := range entries {
+		first, last = min(first, entry.Date), max(last, entry.Date)
+	}
+
+	return fmt.Sprintf("between %s and %s", first, last)
+}
+
+func storeEntriesBatch(entries []Entry) error {
+	url := apiUrl("/time_tracking/clock_entries/store")
+
 	body, _ := json.Marshal(TimeEntriesPostBody{Entries: entries})
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
-		fmt.Printf("Unable to create POST request: %v \n", err)
-		os.Exit(1)
+		return errors.New(fmt.Sprintf("unable to create POST request: %v", err))
 	}
 	req.Header.Add("Content-Type", "application/json")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("Unable to trigger POST request: %v \n", err)
-		os.Exit(1)
+		return errors.New(fmt.Sprintf("unable to trigger POST request: %v", err))
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("Unable to read response body: %v", err)
-		os.Exit(1)
+		return errors.New(fmt.Sprintf("unable to read response body: %v", err))
 	}
 	if resp.StatusCode == http.StatusUnauthorized {
-		fmt.Printf("invalid 'apiToken' provided - API returned 401 (Unauthorized). Aborting \n")
-		os.Exit(1)
+		return errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized)")
 	}
 	if resp.StatusCode == http.StatusBadRequest {
-		fmt.Printf("Received Bad request (400): %s \n", string(respBody))
-		os.Exit(1)
+		return errors.New(fmt.Sprintf("received Bad request (400): %s", string(respBody)))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(respBody)))
 	}
 
-	fmt.Println("Successfully populated working hour entries between two dates. Please double-check in Bamboo")
+	return nil
 }
 
 func fetchWorkingHours() ([]TimeEntry, error) {
-	var getHoursUrlTemplate = "https://%s:x@api.bamboohr.com/api/gateway.php/flaviar/v1/time_tracking/timesheet_entries?employeeIds=%d&start=%s&end=%s"
-	url := fmt.Sprintf(getHoursUrlTemplate, apiKey, employeeId, startDate, endDate)
+	return fetchEmployeesHours([]int{employeeId})
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("failed to get tracked working hours from Bamboo: %v \n", err))
+// fetchEmployeesHours fetches tracked hours of multiple employees in a single request
+func fetchEmployeesHours(ids []int) ([]TimeEntry, error) {
+	employeeIds := make([]string, 0, len(ids))
+	for _, id := range ids {
+		employeeIds = append(employeeIds, strconv.Itoa(id))
 	}
-	defer resp.Body.Close()
+	// BambooHR's end date is inclusive, while end date of the range isn't
+	lastDate := inclusiveEnd(endDate)
+	url := apiUrl("/time_tracking/timesheet_entries?employeeIds=%s&start=%s&end=%s", strings.Join(employeeIds, ","), startDate, lastDate)
+
+	key := cacheKey("timesheet", strings.Join(employeeIds, ","), startDate, lastDate)
+	body, err := cachedFetch(key, func() ([]byte, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to get tracked working hours from Bamboo: %v \n", err))
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to read response body: %v", err))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized). Aborting")
+		}
+		if resp.StatusCode == http.StatusBadRequest {
+			return nil, errors.New(string(body))
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, errors.New(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		}
 
-	body, err := io.ReadAll(resp.Body)
+		return body, nil
+	})
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("failed to read response body: %v", err))
-	}
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized). Aborting")
-	}
-	if resp.StatusCode == http.StatusBadRequest {
-		return nil, errors.New(string(body))
+		return nil, err
 	}
 
 	var workingHours TimeEntries
@@ -190,137 +386,234 @@ func generateWorkEntries(report Report, startDate string, endDate string) ([]Ent
 		return nil, errors.New(fmt.Sprint("max diff between days is 31 days \n", err))
 	}
 
-	weekend := []time.Weekday{time.Saturday, time.Sunday}
-	existingHours := report.days
 	var entries []Entry
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	allocator := newProjectAllocator(schedule.Projects)
+	timeline := reportTimeline(report)
 
 	for s := start; !s.After(end); s = s.AddDate(0, 0, 1) {
 		// exclude end date
 		if s == end {
 			break
 		}
-		// exclude weekends
-		if slices.Contains(weekend, s.Weekday()) {
-			fmt.Printf("Excluded %s because it's a weekend \n", s.Format("2006-01-2"))
-			continue
-		}
-		// exclude days when hours were already logged
-		_, ok := existingHours[s.Format("2006-01-02")]
-		if ok {
-			fmt.Printf("Excluded '%s' because hours were already logged for this day \n", s.Format("2006-01-2"))
-			continue
-		}
-		// exclude holidays
-		holiday, ok := holidays[s.Format("2006-01-02")]
-		if ok {
-			fmt.Printf("Excluded '%s' because it's public holiday - %s \n", s.Format("2006-01-2"), holiday)
+		if decision := evaluateDay(s, report); decision.excluded() {
+			fmt.Printf("Excluded '%s' because of %s \n", s.Format("2006-01-02"), decision.reason())
 			continue
 		}
-		// exclude provided dates (PTOs, collective leave, etc.)
-		_, ok = excludedDays[s.Format("2006-01-02")]
-		if ok {
-			fmt.Printf("Excluded '%s' because you excluded it \n", s.Format("2006-01-2"))
+
+		date := s.Format("2006-01-02")
+		day, violations := compliance.enforce(date, generateDayEntries(s, r, allocator), timeline)
+		if len(violations) > 0 {
+			fmt.Printf("Excluded '%s' because it would break %s labor law: \n", date, compliance.Country)
+			for _, v := range violations {
+				fmt.Printf("  - %s: %s \n", v.Rule, v.Detail)
+			}
 			continue
 		}
+		entries = append(entries, day...)
+	}
 
-		// 440 to 460 minutes (7h20 to 7h40) - excluding 30min for break
-		workMinutes := r.Intn(21) + 440
-		// randomly select either 8 or 8 as the hour, and random minute within the hour (8AM - 9:59AM)
-		morningStart := time.Date(s.Year(), s.Month(), s.Day(), r.Intn(2)+8, r.Intn(60), 0, 0, s.Location())
-		// calculate the halfway of the work duration
-		morningEnd := morningStart.Add(time.Duration(workMinutes/2) * time.Minute)
-		breakStart := morningEnd
-		// 30min lunch break
-		breakEnd := breakStart.Add(30 * time.Minute)
-		afternoonEnd := breakEnd.Add(time.Duration(workMinutes-workMinutes/2) * time.Minute)
-
-		startEntry := Entry{
-			EmployeeId: employeeId,
-			Date:       s.Format("2006-01-02"),
-			Start:      morningStart.Format("15:04"),
-			End:        morningEnd.Format("15:04"),
-		}
+	return entries, nil
+}
 
-		lunchEntry := Entry{
-			EmployeeId: employeeId,
-			Date:       s.Format("2006-01-02"),
-			Start:      breakStart.Format("15:04"),
-			End:        breakEnd.Format("15:04"),
-		}
+// WorkBlockSource derives day's work blocks from recorded activity, eg. git commits
+type WorkBlockSource interface {
+	// workBlocks returns nil when there is no activity for the day
+	workBlocks(date time.Time) []WorkBlock
+}
 
-		endEntry := Entry{
-			EmployeeId: employeeId,
-			Date:       s.Format("2006-01-02"),
-			Start:      breakEnd.Format("15:04"),
-			End:        afternoonEnd.Format("15:04"),
-		}
+// blockSource is used for generating entries when set, days without activity fall back to the randomized template
+var blockSource WorkBlockSource
 
-		entries = append(entries, startEntry, lunchEntry, endEntry)
+// generateDayEntries generates work entries for a single day from recorded activity or randomized template
+func generateDayEntries(s time.Time, r *rand.Rand, allocator *projectAllocator) []Entry {
+	var blocks []WorkBlock
+	if blockSource != nil {
+		blocks = blockSource.workBlocks(s)
+	}
+	if len(blocks) == 0 {
+		blocks = templateBlocks(s, r)
 	}
 
-	return entries, nil
+	// split the day between configured projects
+	if allocator != nil {
+		return allocator.split(s, blocks)
+	}
+
+	var entries []Entry
+	for _, block := range blocks {
+		entries = append(entries, newEntry(s, block, projectId, taskId, note))
+	}
+
+	return entries
 }
 
-func processRequiredHours() {
-	report := getRequiredHours(year, holidays)
+// templateBlocks returns randomized morning and afternoon blocks of the day, split by the 30min lunch break
+func templateBlocks(s time.Time, r *rand.Rand) []WorkBlock {
+	// 470 to 490 minutes (7h50 to 8h10) of work - the break between blocks isn't logged
+	workMinutes := r.Intn(21) + 470
+	// randomly select either 8 or 9 as the hour, and random minute within the hour (8AM - 9:59AM) in employee's time zone
+	morningStart := time.Date(s.Year(), s.Month(), s.Day(), r.Intn(2)+8, r.Intn(60), 0, 0, location)
+	// durations are added to absolute time, so the day keeps its length across DST transitions
+	// calculate the halfway of the work duration
+	morningEnd := morningStart.Add(time.Duration(workMinutes/2) * time.Minute)
+	// 30min lunch break
+	afternoonStart := morningEnd.Add(30 * time.Minute)
+	afternoonEnd := afternoonStart.Add(time.Duration(workMinutes-workMinutes/2) * time.Minute)
+
+	return []WorkBlock{
+		{start: morningStart, end: morningEnd},
+		{start: afternoonStart, end: afternoonEnd},
+	}
+}
+
+func newEntry(date time.Time, block WorkBlock, projectId int, taskId int, note string) Entry {
+	return Entry{
+		EmployeeId: employeeId,
+		Date:       date.Format("2006-01-02"),
+		Start:      block.start.Format("15:04"),
+		End:        block.end.Format("15:04"),
+		ProjectId:  projectId,
+		TaskId:     taskId,
+		Note:       cmp.Or(block.note, note),
+	}
+}
+
+// entryInterval parses entry's start and end time in employee's time zone
+func entryInterval(entry Entry) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02 15:04", entry.Date+" "+entry.Start, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("unable to parse start time '%s': %v", entry.Start, err))
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", entry.Date+" "+entry.End, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(fmt.Sprintf("unable to parse end time '%s': %v", entry.End, err))
+	}
+
+	return start, end, nil
+}
+
+func processRequiredHours(start time.Time, end time.Time, groupBy string, holidays map[string]string) {
+	report, err := getRequiredHoursInRange(start, end, groupBy, holidays)
+	if err != nil {
+		fmt.Printf("Unable to calculate required hours: %v \n", err)
+		os.Exit(1)
+	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
 	defer w.Flush()
 	// table header
-	fmt.Fprintf(w, "Month\tWork Days\tWork Hours\tHolidays\tHoliday Hours\tTotal\t\n")
+	fmt.Fprintf(w, "%s\tWork Days\tWork Hours\tHolidays\tHoliday Hours\tTotal\t\n", periodHeader(groupBy))
 
-	// sort months in asc order because map sorting order is random
-	months := make([]string, 0, len(report.month))
-	for month := range report.month {
-		months = append(months, month)
+	// sort periods in asc order because map sorting order is random
+	periods := make([]string, 0, len(report))
+	for period := range report {
+		periods = append(periods, period)
 	}
-	sort.Strings(months)
+	sort.Strings(periods)
 
-	for _, month := range months {
-		monthDate, err := time.Parse("2006-01", month)
+	totals := MonthReport{}
+	for _, period := range periods {
+		label, err := formatPeriod(period, groupBy)
 		if err != nil {
-			fmt.Printf("Unable to prase date to month: %v \n", err)
+			fmt.Printf("Unable to parse period: %v \n", err)
 			os.Exit(1)
 		}
-		fmt.Fprintf(w, "%s\t%d days\t%dh\t%d days\t%dh\t%dh\n", monthDate.Format("2006 January"), report.month[month].workDays, report.month[month].workHours, report.month[month].holidays, report.month[month].totalHolidayHours, report.month[month].totalHours)
+		r := report[period]
+		fmt.Fprintf(w, "%s\t%d days\t%dh\t%d days\t%dh\t%dh\n", label, r.workDays, r.workHours, r.holidays, r.totalHolidayHours, r.totalHours)
+
+		totals.workDays += r.workDays
+		totals.workHours += r.workHours
+		totals.holidays += r.holidays
+		totals.totalHolidayHours += r.totalHolidayHours
+		totals.totalHours += r.totalHours
+	}
+
+	// print the sum only when there is more than a single period
+	if len(periods) > 1 {
+		fmt.Fprintf(w, "Total\t%d days\t%dh\t%d days\t%dh\t%dh\n", totals.workDays, totals.workHours, totals.holidays, totals.totalHolidayHours, totals.totalHours)
 	}
 }
 
 func getRequiredHours(year int, holidays map[string]string) YearReport {
-	dateMap := make(map[string]MonthReport)
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
 
-	for month := time.January; month <= time.December; month++ {
-		totalDays := 0
-		totalHolidays := 0
-		monthStr := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	// grouping by month never fails, so the error can be ignored
+	report, _ := getRequiredHoursInRange(start, end, GroupByMonth, holidays)
 
-		for day := 1; day <= daysInMonth(month, year); day++ {
-			date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return YearReport{month: report}
+}
 
-			weekend := []time.Weekday{time.Saturday, time.Sunday}
-			// skip weekends
-			if slices.Contains(weekend, date.Weekday()) {
-				continue
-			}
-			// skip public holidays
-			if _, ok := holidays[date.Format("2006-01-02")]; ok {
-				totalHolidays += 1
-			}
+// getRequiredHoursInRange calculates required hours between start (inclusive) and end (exclusive) date,
+// grouped into weeks, months or quarters
+func getRequiredHoursInRange(start time.Time, end time.Time, groupBy string, holidays map[string]string) (map[string]MonthReport, error) {
+	if !slices.Contains(groupings, groupBy) {
+		return nil, errors.New(fmt.Sprintf("unsupported grouping '%s', use one of: %s", groupBy, strings.Join(groupings, ", ")))
+	}
+	if start.After(end) {
+		return nil, errors.New("'end' date cannot be before 'start' date")
+	}
+
+	dateMap := make(map[string]MonthReport)
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
 
-			totalDays += 1
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		period := periodKey(date, groupBy)
+		r := dateMap[period]
+
+		// skip weekends
+		if slices.Contains(weekend, date.Weekday()) {
+			dateMap[period] = r
+			continue
 		}
 
-		dateMap[monthStr.Format("2006-01")] = MonthReport{
-			workDays:          totalDays - totalHolidays,
-			holidays:          totalHolidays,
-			workHours:         (totalDays - totalHolidays) * 8,
-			totalHolidayHours: totalHolidays * 8,
-			totalHours:        totalDays * 8,
+		// public holidays are paid, but not worked
+		if _, ok := holidays[date.Format("2006-01-02")]; ok {
+			r.holidays += 1
+			r.totalHolidayHours += 8
+		} else {
+			r.workDays += 1
+			r.workHours += 8
 		}
+		r.totalHours += 8
+
+		dateMap[period] = r
+	}
+
+	return dateMap, nil
+}
+
+// periodKey returns sortable key of the period the date belongs to eg. 2025-03, 2025-W12 or 2025-Q1
+func periodKey(date time.Time, groupBy string) string {
+	switch groupBy {
+	case GroupByWeek:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case GroupByQuarter:
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	default:
+		return date.Format("2006-01")
+	}
+}
+
+// formatPeriod converts period key into a human friendly label
+func formatPeriod(period string, groupBy string) (string, error) {
+	if groupBy != GroupByMonth {
+		return period, nil
+	}
+
+	monthDate, err := time.Parse("2006-01", period)
+	if err != nil {
+		return "", err
 	}
 
-	return YearReport{month: dateMap}
+	return monthDate.Format("2006 January"), nil
+}
+
+// periodHeader returns table header for the period column eg. Month
+func periodHeader(groupBy string) string {
+	return strings.ToUpper(groupBy[:1]) + groupBy[1:]
 }
 
 func daysInMonth(month time.Month, year int) int {
@@ -335,13 +628,14 @@ func groupHoursByDate(workingHours []TimeEntry) Report {
 	totalHours := 0.0
 
 	for _, entry := range workingHours {
-		dayReport, ok := dateMap[entry.Date]
-		if !ok {
-			dateMap[entry.Date] = DayReport{workHours: entry.Hours}
-			totalHours += entry.Hours
-			continue
-		}
+		dayReport := dateMap[entry.Date]
 		dayReport.workHours += entry.Hours
+		if !entry.Start.IsZero() && (dayReport.start.IsZero() || entry.Start.Before(dayReport.start)) {
+			dayReport.start = entry.Start
+		}
+		if entry.End.After(dayReport.end) {
+			dayReport.end = entry.End
+		}
 		dateMap[entry.Date] = dayReport
 		totalHours += entry.Hours
 	}
@@ -359,34 +653,59 @@ func convertDecimalTimeToTime(decimalTime float64) string {
 	return fmt.Sprintf("%d hours and %d minutes", hours, minutes)
 }
 
-func askForConfirmation(entries []Entry, force bool) (bool, error) {
+func askForConfirmation(entries []Entry, report Report, force bool) ([]Entry, bool, error) {
 	r := bufio.NewReader(os.Stdin)
-	msg := "\nGenerated work entries: \n\n"
-	for _, entry := range entries {
-		msg += fmt.Sprintf("Date: %s ; Start date: %s ; End date: %s \n", entry.Date, entry.Start, entry.End)
-	}
 
 	if force {
-		fmt.Printf("%s\nPopulating your work hours without confirmation \n", msg)
-		return true, nil
+		fmt.Printf("%s\nPopulating your work hours without confirmation \n", formatEntries(entries))
+		return entries, true, nil
 	}
 
 	for {
-		fmt.Printf("%s\nAre you sure you want to populate your work hours with the generated entries listed above? [y/n] ", msg)
+		fmt.Printf("%s\nAre you sure you want to populate your work hours with the generated entries listed above? [y/n/e(dit)] ", formatEntries(entries))
 
 		resp, err := r.ReadString('\n')
 		if err != nil {
-			return false, errors.New(fmt.Sprintf("unable to read string from user: %v", err))
+			return nil, false, errors.New(fmt.Sprintf("unable to read string from user: %v", err))
 		}
 
 		resp = strings.ToLower(strings.TrimSpace(resp))
 		switch resp {
 		case "y", "yes":
-			return true, nil
+			return entries, true, nil
 		case "n", "no":
-			return false, nil
+			return nil, false, nil
+		case "e", "edit":
+			entries, err = reviewEntries(r, entries, report)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(entries) == 0 {
+				fmt.Println("All generated entries were dropped")
+				return nil, false, nil
+			}
+			continue
 		}
 
 		fmt.Println("You selected invalid option, retrying... Press Ctrl+c to exit")
 	}
 }
+
+func formatEntries(entries []Entry) string {
+	msg := "\nGenerated work entries: \n\n"
+	for _, entry := range entries {
+		msg += fmt.Sprintf("Date: %s ; Start date: %s ; End date: %s", entry.Date, entry.Start, entry.End)
+		if entry.ProjectId > 0 {
+			msg += fmt.Sprintf(" ; Project: %d", entry.ProjectId)
+		}
+		if entry.TaskId > 0 {
+			msg += fmt.Sprintf(" ; Task: %d", entry.TaskId)
+		}
+		if entry.Note != "" {
+			msg += fmt.Sprintf(" ; Note: %s", entry.Note)
+		}
+		msg += " \n"
+	}
+
+	return msg
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"slices"
 	"sort"
@@ -23,7 +26,7 @@ func TestGenerateWorkEntries(t *testing.T) {
 			"ValidWeek",
 			args{
 				Report{
-					map[string]DayReport{"2024-11-05": {7.7}},
+					map[string]DayReport{"2024-11-05": {workHours: 7.7}},
 					7.7,
 				},
 				"2024-10-25",
@@ -40,8 +43,8 @@ func TestGenerateWorkEntries(t *testing.T) {
 				t.Errorf("generateWorkEntries() error = %v, wantErr %v", err, test.wantErr)
 				return
 			}
-			if len(got) != 7*3 {
-				t.Errorf("generateWorkEntries() should have exactly 21 work entries (3x for each day, 7 work days in total), got %d", len(got))
+			if len(got) != 7*2 {
+				t.Errorf("generateWorkEntries() should have exactly 14 work entries (2x for each day, 7 work days in total), got %d", len(got))
 				return
 			}
 			weekendDays := []string{"2024-10-26", "2024-10-27", "2024-11-02", "2024-11-03"}
@@ -199,3 +202,217 @@ func getMonthsInMap(monthsMap map[string]MonthReport) []string {
 	sort.Strings(months)
 	return months
 }
+
+func TestGetRequiredHoursInRange(t *testing.T) {
+	type args struct {
+		start    string
+		end      string
+		groupBy  string
+		holidays map[string]string
+	}
+
+	tests := []struct {
+		name    string
+		input   args
+		want    map[string]MonthReport
+		wantErr bool
+	}{
+		{
+			"SingleMonth",
+			args{"2024-02-01", "2024-03-01", GroupByMonth, map[string]string{"2024-02-08": "Prešernov dan"}},
+			map[string]MonthReport{"2024-02": {20, 1, 160, 8, 168}},
+			false,
+		},
+		{
+			"WeeksAcrossMonths",
+			args{"2025-03-24", "2025-04-07", GroupByWeek, map[string]string{}},
+			map[string]MonthReport{
+				"2025-W13": {5, 0, 40, 0, 40},
+				"2025-W14": {5, 0, 40, 0, 40},
+			},
+			false,
+		},
+		{
+			"FirstQuarter",
+			args{"2024-01-01", "2024-04-01", GroupByQuarter, map[string]string{"2024-01-01": "novo leto", "2024-01-02": "novo leto"}},
+			map[string]MonthReport{"2024-Q1": {63, 2, 504, 16, 520}},
+			false,
+		},
+		{
+			"UnsupportedGrouping",
+			args{"2024-01-01", "2024-04-01", "day", map[string]string{}},
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, _ := time.Parse("2006-01-02", test.input.start)
+			end, _ := time.Parse("2006-01-02", test.input.end)
+
+			got, err := getRequiredHoursInRange(start, end, test.input.groupBy, test.input.holidays)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("getRequiredHoursInRange() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("getRequiredHoursInRange() want = %v ; got = %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateWorkEntriesInTimezone(t *testing.T) {
+	loc := useLjubljana(t)
+
+	// week with transition to summer time (2025-03-30 02:00 CET -> 03:00 CEST)
+	got, err := generateWorkEntries(Report{days: map[string]DayReport{}}, "2025-03-27", "2025-04-02")
+	if err != nil {
+		t.Fatalf("generateWorkEntries() error = %v", err)
+	}
+	if len(got) != 4*2 {
+		t.Fatalf("generateWorkEntries() should have 8 work entries, got %d", len(got))
+	}
+
+	for i := 0; i < len(got); i += 2 {
+		morning, afternoon := got[i], got[i+1]
+		dayStart, _ := time.ParseInLocation("2006-01-02 15:04", morning.Date+" "+morning.Start, loc)
+		dayEnd, _ := time.ParseInLocation("2006-01-02 15:04", afternoon.Date+" "+afternoon.End, loc)
+
+		// days before the transition are in CET (UTC+1), days after it in CEST (UTC+2)
+		wantOffset := 2 * 60 * 60
+		if morning.Date < "2025-03-30" {
+			wantOffset = 1 * 60 * 60
+		}
+		if _, offset := dayStart.Zone(); offset != wantOffset {
+			t.Errorf("generateWorkEntries() day %s should start at UTC offset %d, got %d", morning.Date, wantOffset, offset)
+		}
+		// the day starts between 8AM and 9:59AM of local time on both sides of the transition
+		if hour := dayStart.Hour(); hour < 8 || hour > 9 {
+			t.Errorf("generateWorkEntries() day %s should start between 08:00 and 09:59 local time, got %s", morning.Date, morning.Start)
+		}
+		lunchStart, _ := time.ParseInLocation("2006-01-02 15:04", morning.Date+" "+morning.End, loc)
+		lunchEnd, _ := time.ParseInLocation("2006-01-02 15:04", afternoon.Date+" "+afternoon.Start, loc)
+		if lunch := lunchEnd.Sub(lunchStart); lunch != 30*time.Minute {
+			t.Errorf("generateWorkEntries() day %s should have a single 30min lunch break, got %v", morning.Date, lunch)
+		}
+		span := dayEnd.Sub(dayStart).Minutes()
+		if span < 470+30 || span > 490+30 {
+			t.Errorf("generateWorkEntries() day %s should span between 8h20 and 8h40, got %v minutes", morning.Date, span)
+		}
+	}
+}
+
+func TestTemplateBlocksAcrossTransition(t *testing.T) {
+	loc := useLjubljana(t)
+	// transition to winter time (2025-10-26 03:00 CEST -> 02:00 CET) happens before the work day
+	date := time.Date(2025, 10, 26, 0, 0, 0, 0, time.UTC)
+
+	blocks := templateBlocks(date, rand.New(rand.NewSource(1)))
+
+	midnight := time.Date(2025, 10, 26, 0, 0, 0, 0, loc)
+	if _, offset := midnight.Zone(); offset != 2*60*60 {
+		t.Fatalf("midnight of %s should be in CEST, got offset %d", date.Format("2006-01-02"), offset)
+	}
+	for _, block := range blocks {
+		if _, offset := block.start.Zone(); offset != 1*60*60 {
+			t.Errorf("templateBlocks() block %s should be in CET after the transition, got offset %d", block.start.Format("15:04"), offset)
+		}
+	}
+	// 25 hours pass between midnight and the next midnight, but work blocks are measured in absolute time
+	work := blocks[0].end.Sub(blocks[0].start) + blocks[1].end.Sub(blocks[1].start)
+	if work < 470*time.Minute || work > 490*time.Minute {
+		t.Errorf("templateBlocks() should have between 7h50 and 8h10 of work, got %v", work)
+	}
+}
+
+func TestGroupHoursByDate(t *testing.T) {
+	day := func(hour int, minute int) time.Time {
+		return time.Date(2025, time.March, 14, hour, minute, 0, 0, time.UTC)
+	}
+	entries := []TimeEntry{
+		{Date: "2025-03-14", Start: day(12, 30), End: day(16, 0), Hours: 3.5},
+		{Date: "2025-03-14", Start: day(7, 0), End: day(12, 0), Hours: 5},
+		{Date: "2025-03-17", Hours: 8},
+	}
+
+	got := groupHoursByDate(entries)
+
+	want := Report{
+		map[string]DayReport{
+			"2025-03-14": {workHours: 8.5, start: day(7, 0), end: day(16, 0)},
+			"2025-03-17": {workHours: 8},
+		},
+		16.5,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("groupHoursByDate() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterEntries(t *testing.T) {
+	entries := []TimeEntry{
+		{Id: 1, Type: "timeEntry", Approved: true},
+		{Id: 2, Type: "timeEntry"},
+		{Id: 3, Type: "hour"},
+	}
+
+	tests := []struct {
+		name       string
+		unapproved bool
+		entryType  string
+		want       []int
+	}{
+		{"NoFilters", false, "", []int{1, 2, 3}},
+		{"Unapproved", true, "", []int{2, 3}},
+		{"Type", false, "TimeEntry", []int{1, 2}},
+		{"UnapprovedType", true, "hour", []int{3}},
+		{"NoMatch", false, "clock", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []int
+			for _, entry := range filterEntries(entries, test.unapproved, test.entryType) {
+				got = append(got, entry.Id)
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("filterEntries() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStoreEntriesPartialFailure(t *testing.T) {
+	useCacheDir(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "maintenance"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	originalBaseUrl := baseUrl
+	baseUrl = server.URL
+	t.Cleanup(func() { baseUrl = originalBaseUrl })
+
+	var entries []Entry
+	for i := range maxEntriesPerRequest + 10 {
+		date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i).Format("2006-01-02")
+		entries = append(entries, Entry{Date: date, Start: "08:00", End: "16:00"})
+	}
+
+	err := storeEntries(entries)
+
+	want := "API returned 503: {\"error\": \"maintenance\"} - 100 entries between 2025-01-01 and 2025-04-10 were pushed, 10 entries between 2025-04-11 and 2025-04-20 weren't"
+	if err == nil || err.Error() != want {
+		t.Errorf("storeEntries() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportEntry is a single clock entry written to the export file
+type ExportEntry struct {
+	Id         int     `json:"id"`
+	Date       string  `json:"date"`
+	Type       string  `json:"type"`
+	Start      string  `json:"start"`
+	End        string  `json:"end"`
+	Hours      float64 `json:"hours"`
+	Timezone   string  `json:"timezone"`
+	ProjectId  int     `json:"projectId,omitempty"`
+	Project    string  `json:"project,omitempty"`
+	TaskId     int     `json:"taskId,omitempty"`
+	Task       string  `json:"task,omitempty"`
+	Note       string  `json:"note"`
+	Approved   bool    `json:"approved"`
+	ApprovedAt string  `json:"approvedAt,omitempty"`
+}
+
+// exportEntries writes raw clock entries into CSV, JSON or iCalendar file, based on the file extension
+func exportEntries(entries []TimeEntry, path string) {
+	if path == "" {
+		fmt.Println("Missing export file eg. 'export timesheet.csv'. Aborting")
+		os.Exit(1)
+	}
+
+	// the format is checked first, so an existing file isn't truncated by an unsupported export
+	writeExport, err := exportWriter(path)
+	if err != nil {
+		fmt.Printf("Unable to export entries: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("Unable to create export file: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	sorted := sortTimeEntries(entries)
+	if err := writeExport(file, sorted); err != nil {
+		fmt.Printf("Unable to export entries: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Exported %d entries to %s \n", len(sorted), path)
+}
+
+// exportWriter returns writer of the export format selected by the file extension
+func exportWriter(path string) (func(io.Writer, []TimeEntry) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return writeExportCsv, nil
+	case ".json":
+		return writeExportJson, nil
+	case ".ics":
+		return writeExportIcs, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("unsupported file type '%s', use .csv, .json or .ics", filepath.Ext(path)))
+}
+
+func newExportEntry(entry TimeEntry) ExportEntry {
+	export := ExportEntry{
+		Id:       entry.Id,
+		Date:     entry.Date,
+		Type:     entry.Type,
+		Hours:    entry.Hours,
+		Timezone: entry.Timezone,
+		Note:     entry.Note,
+		Approved: entry.Approved,
+	}
+	if !entry.Start.IsZero() {
+		export.Start = entry.Start.In(location).Format("15:04")
+	}
+	if !entry.End.IsZero() {
+		export.End = entry.End.In(location).Format("15:04")
+	}
+	if !entry.ApprovedAt.IsZero() {
+		export.ApprovedAt = entry.ApprovedAt.Format(time.RFC3339)
+	}
+	if entry.ProjectInfo != nil {
+		export.ProjectId = entry.ProjectInfo.Project.Id
+		export.Project = entry.ProjectInfo.Project.Name
+		if entry.ProjectInfo.Task != nil {
+			export.TaskId = entry.ProjectInfo.Task.Id
+			export.Task = entry.ProjectInfo.Task.Name
+		}
+	}
+
+	return export
+}
+
+func writeExportCsv(w io.Writer, entries []TimeEntry) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"id", "date", "type", "start", "end", "hours", "timezone", "projectId", "project", "taskId", "task", "note", "approved", "approvedAt"})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		e := newExportEntry(entry)
+		err := cw.Write([]string{
+			strconv.Itoa(e.Id), e.Date, e.Type, e.Start, e.End, strconv.FormatFloat(e.Hours, 'f', -1, 64), e.Timezone,
+			optionalId(e.ProjectId), e.Project, optionalId(e.TaskId), e.Task, e.Note, strconv.FormatBool(e.Approved), e.ApprovedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func optionalId(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func writeExportJson(w io.Writer, entries []TimeEntry) error {
+	export := make([]ExportEntry, 0, len(entries))
+	for _, entry := range entries {
+		export = append(export, newExportEntry(entry))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(export)
+}
+
+// writeExportIcs writes one VEVENT per clock entry. Entries tracked as hours without start and end time
+// can't be placed into the calendar and are skipped
+func writeExportIcs(w io.Writer, entries []TimeEntry) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//mluksic//bamboo//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:BambooHR timesheet",
+	}
+	stamp := clock().UTC().Format("20060102T150405Z")
+
+	skipped := 0
+	for _, entry := range entries {
+		if entry.Start.IsZero() || entry.End.IsZero() {
+			skipped++
+			continue
+		}
+		e := newExportEntry(entry)
+
+		summary := "Work"
+		if e.Project != "" {
+			summary = e.Project
+			if e.Task != "" {
+				summary += " - " + e.Task
+			}
+		}
+		approval := "Not approved"
+		status := "TENTATIVE"
+		if e.Approved {
+			approval = "Approved"
+			if e.ApprovedAt != "" {
+				approval += " at " + e.ApprovedAt
+			}
+			status = "CONFIRMED"
+		}
+		description := approval
+		if e.Note != "" {
+			description = e.Note + "\n" + approval
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:bamboohr-timesheet-%d-%d", entry.EmployeeId, entry.Id),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+entry.Start.UTC().Format("20060102T150405Z"),
+			"DTEND:"+entry.End.UTC().Format("20060102T150405Z"),
+			"SUMMARY:"+escapeText(summary),
+			"DESCRIPTION:"+escapeText(description),
+			"STATUS:"+status,
+			"TRANSP:OPAQUE",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipped %d entries without start and end time \n", skipped)
+	}
+
+	return nil
+}
+
+func escapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// foldLine splits lines longer than 75 octets into multiple lines, without breaking multibyte characters
+func foldLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportFixture(t *testing.T) []TimeEntry {
+	loc := useLjubljana(t)
+	at := func(clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", "2025-03-14 "+clock, loc)
+		return t
+	}
+
+	return []TimeEntry{
+		{
+			Id: 1, EmployeeId: 123, Type: "timeEntry", Date: "2025-03-14", Start: at("08:00"), End: at("12:00"),
+			Timezone: "Europe/Ljubljana", Hours: 4, Note: "Code review, API",
+			ProjectInfo: &ProjectInfo{Project: Project{Id: 5, Name: "Website"}, Task: &Task{Id: 12, Name: "Development"}},
+			Approved:    true, ApprovedAt: time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC),
+		},
+		{Id: 2, EmployeeId: 123, Type: "timeEntry", Date: "2025-03-14", Start: at("12:00"), End: at("16:30"), Timezone: "Europe/Ljubljana", Hours: 4.5},
+		{Id: 3, EmployeeId: 123, Type: "hour", Date: "2025-03-13", Hours: 8},
+	}
+}
+
+func TestWriteExportCsv(t *testing.T) {
+	entries := exportFixture(t)
+	want := "id,date,type,start,end,hours,timezone,projectId,project,taskId,task,note,approved,approvedAt\n" +
+		"1,2025-03-14,timeEntry,08:00,12:00,4,Europe/Ljubljana,5,Website,12,Development,\"Code review, API\",true,2025-03-17T09:00:00Z\n" +
+		"2,2025-03-14,timeEntry,12:00,16:30,4.5,Europe/Ljubljana,,,,,,false,\n" +
+		"3,2025-03-13,hour,,,8,,,,,,,false,\n"
+
+	var b bytes.Buffer
+	if err := writeExportCsv(&b, entries); err != nil {
+		t.Fatalf("writeExportCsv() error = %v", err)
+	}
+
+	if b.String() != want {
+		t.Errorf("writeExportCsv() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteExportJson(t *testing.T) {
+	entries := exportFixture(t)
+
+	var b bytes.Buffer
+	if err := writeExportJson(&b, entries); err != nil {
+		t.Fatalf("writeExportJson() error = %v", err)
+	}
+	var got []ExportEntry
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("writeExportJson() wrote invalid JSON: %v", err)
+	}
+
+	want := ExportEntry{
+		Id: 1, Date: "2025-03-14", Type: "timeEntry", Start: "08:00", End: "12:00", Hours: 4, Timezone: "Europe/Ljubljana",
+		ProjectId: 5, Project: "Website", TaskId: 12, Task: "Development", Note: "Code review, API",
+		Approved: true, ApprovedAt: "2025-03-17T09:00:00Z",
+	}
+	if len(got) != 3 || !reflect.DeepEqual(want, got[0]) {
+		t.Errorf("writeExportJson() = %v, want first entry %v", got, want)
+	}
+}
+
+func TestWriteExportIcs(t *testing.T) {
+	pinClock(t, "2025-03-18 10:00")
+	entries := exportFixture(t)
+
+	var b bytes.Buffer
+	if err := writeExportIcs(&b, entries); err != nil {
+		t.Fatalf("writeExportIcs() error = %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("writeExportIcs() line %q is longer than 75 octets", line)
+		}
+	}
+	if !strings.Contains(b.String(), "STATUS:CONFIRMED\r\n") || !strings.Contains(b.String(), "STATUS:TENTATIVE\r\n") {
+		t.Errorf("writeExportIcs() should mark approved entries as confirmed and the rest as tentative")
+	}
+
+	// the export should be readable by the calendar generator
+	events, err := parseCalendar(&b)
+	if err != nil {
+		t.Fatalf("parseCalendar() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("writeExportIcs() should write 2 events, got %d", len(events))
+	}
+	if events[0].summary != "Website - Development" || !events[0].start.Equal(entries[0].Start) || !events[0].end.Equal(entries[0].End) {
+		t.Errorf("writeExportIcs() first event = %v, want %v", events[0], entries[0])
+	}
+	if events[1].summary != "Work" {
+		t.Errorf("writeExportIcs() second event summary = %q, want %q", events[1].summary, "Work")
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("č", 40)
+
+	got := foldLine(line)
+
+	for _, part := range strings.Split(got, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("foldLine() part %q is longer than 75 octets", part)
+		}
+	}
+	if unfolded := strings.ReplaceAll(got, "\r\n ", ""); unfolded != line {
+		t.Errorf("foldLine() unfolded = %q, want %q", unfolded, line)
+	}
+}
+
+func TestExportWriter(t *testing.T) {
+	for _, path := range []string{"timesheet.csv", "timesheet.JSON", "calendar.ics"} {
+		if _, err := exportWriter(path); err != nil {
+			t.Errorf("exportWriter(%q) error = %v", path, err)
+		}
+	}
+	if _, err := exportWriter("notes.txt"); err == nil {
+		t.Errorf("exportWriter(notes.txt) should return error")
+	}
+}
@@ -0,0 +1,487 @@
+// Package fakebamboo is an in-memory stand-in for the BambooHR API endpoints used by the app. It's meant for
+// local and end-to-end testing, so the app never talks to the real API
+package fakebamboo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BasePath is the path of the fake API, the app's base URL is the server's address followed by it
+const BasePath = "/api/gateway.php/flaviar/v1"
+
+type TimeEntry struct {
+	Id          int          `json:"id"`
+	EmployeeId  int          `json:"employeeId"`
+	Type        string       `json:"type"`
+	Date        string       `json:"date"`
+	Start       *time.Time   `json:"start,omitempty"`
+	End         *time.Time   `json:"end,omitempty"`
+	Timezone    string       `json:"timezone"`
+	Hours       float64      `json:"hours"`
+	Note        string       `json:"note,omitempty"`
+	ProjectInfo *ProjectInfo `json:"projectInfo,omitempty"`
+	Approved    bool         `json:"approved"`
+	ApprovedAt  *time.Time   `json:"approvedAt,omitempty"`
+}
+
+// TimeOff is a who's out entry, company holidays have 'holiday' type, which seeded entries without employee and
+// type get by default
+type TimeOff struct {
+	Id         int    `json:"id"`
+	Type       string `json:"type"`
+	EmployeeId int    `json:"employeeId,omitempty"`
+	Name       string `json:"name"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+}
+
+type Project struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Billable bool   `json:"billable"`
+	HasTasks bool   `json:"hasTasks"`
+	Tasks    []Task `json:"tasks"`
+}
+
+type Task struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Billable bool   `json:"billable"`
+}
+
+type ProjectInfo struct {
+	Project Project `json:"project"`
+	Task    *Task   `json:"task,omitempty"`
+}
+
+// Seed is the server's initial data
+type Seed struct {
+	// ApiKey is required from clients when set, any key is accepted otherwise
+	ApiKey string `json:"apiKey"`
+	// Timezone of stored entries, defaults to UTC
+	Timezone string      `json:"timezone"`
+	Entries  []TimeEntry `json:"entries"`
+	TimeOff  []TimeOff   `json:"timeOff"`
+	Projects []Project   `json:"projects"`
+}
+
+// StoreEntry is a single entry of the clock entries store request
+type StoreEntry struct {
+	EmployeeId int    `json:"employeeId"`
+	Date       string `json:"date"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	ProjectId  int    `json:"projectId,omitempty"`
+	TaskId     int    `json:"taskId,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+type ClockBody struct {
+	ProjectId int    `json:"projectId,omitempty"`
+	TaskId    int    `json:"taskId,omitempty"`
+	Note      string `json:"note,omitempty"`
+	Date      string `json:"date"`
+	Start     string `json:"start,omitempty"`
+	End       string `json:"end,omitempty"`
+	Timezone  string `json:"timezone"`
+}
+
+// Server keeps entries in memory, so every server starts from its seed
+type Server struct {
+	mu       sync.Mutex
+	apiKey   string
+	location *time.Location
+	entries  []TimeEntry
+	timeOff  []TimeOff
+	projects []Project
+	nextId   int
+	mux      *http.ServeMux
+}
+
+// LoadSeed reads seed from JSON file
+func LoadSeed(path string) (Seed, error) {
+	var seed Seed
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return seed, errors.New(fmt.Sprintf("unable to read seed file: %v", err))
+	}
+	if err := json.Unmarshal(file, &seed); err != nil {
+		return seed, errors.New(fmt.Sprintf("unable to parse seed file '%s': %v", path, err))
+	}
+
+	return seed, nil
+}
+
+func New(seed Seed) (*Server, error) {
+	location, err := time.LoadLocation(seed.Timezone)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid timezone '%s': %v", seed.Timezone, err))
+	}
+
+	s := &Server{
+		apiKey:   seed.ApiKey,
+		location: location,
+		projects: slices.Clone(seed.Projects),
+	}
+	for _, out := range seed.TimeOff {
+		if out.Type == "" && out.EmployeeId == 0 {
+			out.Type = "holiday"
+		}
+		s.timeOff = append(s.timeOff, out)
+	}
+	for _, entry := range seed.Entries {
+		if entry.Timezone == "" {
+			entry.Timezone = location.String()
+		}
+		if entry.Type == "" {
+			entry.Type = "clock"
+		}
+		if entry.Hours == 0 && entry.Start != nil && entry.End != nil {
+			entry.Hours = entry.End.Sub(*entry.Start).Hours()
+		}
+		s.entries = append(s.entries, entry)
+		s.nextId = max(s.nextId, entry.Id)
+	}
+	// seeded entries without ID get one as well
+	for i := range s.entries {
+		if s.entries[i].Id == 0 {
+			s.nextId++
+			s.entries[i].Id = s.nextId
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+BasePath+"/time_tracking/timesheet_entries", s.handleTimesheetEntries)
+	mux.HandleFunc("POST "+BasePath+"/time_tracking/clock_entries/store", s.handleStore)
+	mux.HandleFunc("POST "+BasePath+"/time_tracking/clock_entries/delete", s.handleDelete)
+	mux.HandleFunc("POST "+BasePath+"/time_tracking/employees/{id}/clock_in", s.handleClockIn)
+	mux.HandleFunc("POST "+BasePath+"/time_tracking/employees/{id}/clock_out", s.handleClockOut)
+	mux.HandleFunc("GET "+BasePath+"/time_tracking/employees/{id}/projects", s.handleProjects)
+	mux.HandleFunc("GET "+BasePath+"/time_off/whos_out", s.handleWhosOut)
+	s.mux = mux
+
+	return s, nil
+}
+
+// ServeHTTP checks the API key, which BambooHR expects as basic auth username
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, _, ok := r.BasicAuth(); s.apiKey != "" && (!ok || user != s.apiKey) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid API key"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mux.ServeHTTP(w, r)
+}
+
+// Entries returns copy of the stored entries, sorted by employee, date and start
+func (s *Server) Entries() []TimeEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := slices.Clone(s.entries)
+	slices.SortStableFunc(entries, func(a, b TimeEntry) int {
+		if a.EmployeeId != b.EmployeeId {
+			return a.EmployeeId - b.EmployeeId
+		}
+		if a.Date != b.Date {
+			return strings.Compare(a.Date, b.Date)
+		}
+		return startOf(a).Compare(startOf(b))
+	})
+
+	return entries
+}
+
+func (s *Server) handleTimesheetEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	start, end, err := parseRange(query.Get("start"), query.Get("end"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var ids []int
+	if value := query.Get("employeeIds"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.Atoi(part)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("invalid employee ID '%s'", part)))
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	entries := []TimeEntry{}
+	for _, entry := range s.entries {
+		if len(ids) > 0 && !slices.Contains(ids, entry.EmployeeId) {
+			continue
+		}
+		// the range is inclusive, same as in BambooHR
+		if entry.Date < start || entry.Date > end {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	writeJson(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleStore(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Entries []StoreEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("unable to parse body: %v", err)))
+		return
+	}
+
+	// every entry is validated first, so invalid request doesn't store anything
+	var created []TimeEntry
+	for i, storeEntry := range body.Entries {
+		start, err := time.ParseInLocation("2006-01-02 15:04", storeEntry.Date+" "+storeEntry.Start, s.location)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("entry #%d: invalid date or start", i+1)))
+			return
+		}
+		end, err := time.ParseInLocation("2006-01-02 15:04", storeEntry.Date+" "+storeEntry.End, s.location)
+		if err != nil || !end.After(start) {
+			writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("entry #%d: end should be after start", i+1)))
+			return
+		}
+		entry := TimeEntry{
+			EmployeeId:  storeEntry.EmployeeId,
+			Type:        "clock",
+			Date:        storeEntry.Date,
+			Start:       &start,
+			End:         &end,
+			Timezone:    s.location.String(),
+			Hours:       end.Sub(start).Hours(),
+			Note:        storeEntry.Note,
+			ProjectInfo: s.projectInfo(storeEntry.ProjectId, storeEntry.TaskId),
+		}
+		if other, ok := overlapping(append(slices.Clone(s.entries), created...), entry); ok {
+			writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("entry #%d: overlaps with entry %s-%s on %s", i+1,
+				startOf(other).In(s.location).Format("15:04"), endOf(other).In(s.location).Format("15:04"), entry.Date)))
+			return
+		}
+		created = append(created, entry)
+	}
+
+	for i := range created {
+		s.nextId++
+		created[i].Id = s.nextId
+	}
+	s.entries = append(s.entries, created...)
+
+	writeJson(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ClockEntryIds []int `json:"clockEntryIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("unable to parse body: %v", err)))
+		return
+	}
+	for _, id := range body.ClockEntryIds {
+		if !slices.ContainsFunc(s.entries, func(entry TimeEntry) bool { return entry.Id == id }) {
+			writeError(w, http.StatusNotFound, errors.New(fmt.Sprintf("clock entry %d doesn't exist", id)))
+			return
+		}
+	}
+
+	s.entries = slices.DeleteFunc(s.entries, func(entry TimeEntry) bool { return slices.Contains(body.ClockEntryIds, entry.Id) })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleClockIn(w http.ResponseWriter, r *http.Request) {
+	employeeId, body, start, ok := s.readClock(w, r, "start")
+	if !ok {
+		return
+	}
+	if slices.ContainsFunc(s.entries, func(entry TimeEntry) bool { return entry.EmployeeId == employeeId && entry.End == nil }) {
+		writeError(w, http.StatusBadRequest, errors.New("employee is already clocked in"))
+		return
+	}
+
+	s.nextId++
+	entry := TimeEntry{
+		Id:          s.nextId,
+		EmployeeId:  employeeId,
+		Type:        "clock",
+		Date:        body.Date,
+		Start:       &start,
+		Timezone:    s.location.String(),
+		Note:        body.Note,
+		ProjectInfo: s.projectInfo(body.ProjectId, body.TaskId),
+	}
+	s.entries = append(s.entries, entry)
+
+	writeJson(w, http.StatusOK, entry)
+}
+
+func (s *Server) handleClockOut(w http.ResponseWriter, r *http.Request) {
+	employeeId, _, end, ok := s.readClock(w, r, "end")
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(s.entries, func(entry TimeEntry) bool { return entry.EmployeeId == employeeId && entry.End == nil })
+	if i < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("employee isn't clocked in"))
+		return
+	}
+	if !end.After(*s.entries[i].Start) {
+		writeError(w, http.StatusBadRequest, errors.New("clock out should be after clock in"))
+		return
+	}
+
+	s.entries[i].End = &end
+	s.entries[i].Hours = end.Sub(*s.entries[i].Start).Hours()
+
+	writeJson(w, http.StatusOK, s.entries[i])
+}
+
+// readClock parses clock in or out request, writing the error response when it's invalid
+func (s *Server) readClock(w http.ResponseWriter, r *http.Request, field string) (int, ClockBody, time.Time, bool) {
+	var body ClockBody
+	employeeId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid employee ID"))
+		return 0, body, time.Time{}, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("unable to parse body: %v", err)))
+		return 0, body, time.Time{}, false
+	}
+	location := s.location
+	if body.Timezone != "" {
+		location, err = time.LoadLocation(body.Timezone)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("invalid timezone '%s'", body.Timezone)))
+			return 0, body, time.Time{}, false
+		}
+	}
+	value := body.Start
+	if field == "end" {
+		value = body.End
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", body.Date+" "+value, location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("invalid date or %s", field)))
+		return 0, body, time.Time{}, false
+	}
+
+	return employeeId, body, t, true
+}
+
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	projects := s.projects
+	if projects == nil {
+		projects = []Project{}
+	}
+
+	writeJson(w, http.StatusOK, projects)
+}
+
+func (s *Server) handleWhosOut(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseRange(r.URL.Query().Get("start"), r.URL.Query().Get("end"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	timeOff := []TimeOff{}
+	for _, out := range s.timeOff {
+		if out.End >= start && out.Start <= end {
+			timeOff = append(timeOff, out)
+		}
+	}
+
+	writeJson(w, http.StatusOK, timeOff)
+}
+
+// projectInfo returns the seeded project and task, unknown projects are returned with their ID only
+func (s *Server) projectInfo(projectId int, taskId int) *ProjectInfo {
+	if projectId == 0 {
+		return nil
+	}
+	info := &ProjectInfo{Project: Project{Id: projectId}}
+	if i := slices.IndexFunc(s.projects, func(p Project) bool { return p.Id == projectId }); i >= 0 {
+		info.Project = s.projects[i]
+	}
+	if taskId > 0 {
+		task := Task{Id: taskId}
+		if i := slices.IndexFunc(info.Project.Tasks, func(t Task) bool { return t.Id == taskId }); i >= 0 {
+			task = info.Project.Tasks[i]
+		}
+		info.Task = &task
+	}
+	info.Project.Tasks = nil
+
+	return info
+}
+
+// overlapping returns entry of the same employee, which overlaps with the given entry
+func overlapping(entries []TimeEntry, entry TimeEntry) (TimeEntry, bool) {
+	for _, other := range entries {
+		if other.EmployeeId != entry.EmployeeId || other.Start == nil {
+			continue
+		}
+		// running entry lasts until it's clocked out
+		if startOf(entry).Before(endOf(other)) && startOf(other).Before(endOf(entry)) {
+			return other, true
+		}
+	}
+
+	return TimeEntry{}, false
+}
+
+func startOf(entry TimeEntry) time.Time {
+	if entry.Start == nil {
+		return time.Time{}
+	}
+
+	return *entry.Start
+}
+
+func endOf(entry TimeEntry) time.Time {
+	if entry.End == nil {
+		return startOf(entry).Add(24 * time.Hour)
+	}
+
+	return *entry.End
+}
+
+func parseRange(start string, end string) (string, string, error) {
+	if _, err := time.Parse("2006-01-02", start); err != nil {
+		return "", "", errors.New("missing or invalid 'start' date")
+	}
+	if _, err := time.Parse("2006-01-02", end); err != nil {
+		return "", "", errors.New("missing or invalid 'end' date")
+	}
+
+	return start, end, nil
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,151 @@
+package fakebamboo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, seed Seed) (*Server, *httptest.Server) {
+	t.Helper()
+	server, err := New(seed)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, httpServer
+}
+
+func request(t *testing.T, method string, url string, body any) *http.Response {
+	t.Helper()
+	var reqBody bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&reqBody).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &reqBody)
+	req.SetBasicAuth("key", "x")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func TestTimesheetEntries(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	_, server := newTestServer(t, Seed{Entries: []TimeEntry{
+		{EmployeeId: 12, Date: "2025-03-10", Start: &start, End: &end},
+		{EmployeeId: 12, Date: "2025-03-20", Start: &start, End: &end},
+		{EmployeeId: 34, Date: "2025-03-10", Start: &start, End: &end},
+	}})
+
+	resp := request(t, http.MethodGet, server.URL+BasePath+"/time_tracking/timesheet_entries?employeeIds=12&start=2025-03-10&end=2025-03-14", nil)
+
+	var entries []TimeEntry
+	json.NewDecoder(resp.Body).Decode(&entries)
+	if resp.StatusCode != http.StatusOK || len(entries) != 1 {
+		t.Fatalf("GET timesheet_entries = %d %v, want single entry", resp.StatusCode, entries)
+	}
+	if entries[0].Id != 1 || entries[0].Hours != 8 || entries[0].Timezone != "UTC" {
+		t.Errorf("GET timesheet_entries = %v, want seeded entry with ID, hours and timezone", entries[0])
+	}
+
+	if resp := request(t, http.MethodGet, server.URL+BasePath+"/time_tracking/timesheet_entries?employeeIds=12", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET timesheet_entries without range = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestStoreAndDelete(t *testing.T) {
+	fake, server := newTestServer(t, Seed{Projects: []Project{{Id: 5, Name: "Development", Tasks: []Task{{Id: 7, Name: "Backend"}}}}})
+	store := server.URL + BasePath + "/time_tracking/clock_entries/store"
+
+	resp := request(t, http.MethodPost, store, map[string][]StoreEntry{"entries": {
+		{EmployeeId: 12, Date: "2025-03-10", Start: "08:00", End: "12:00", ProjectId: 5, TaskId: 7},
+		{EmployeeId: 12, Date: "2025-03-10", Start: "12:30", End: "16:30"},
+	}})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST store = %d, want 201", resp.StatusCode)
+	}
+	entries := fake.Entries()
+	if len(entries) != 2 || entries[0].Hours != 4 || entries[0].ProjectInfo.Project.Name != "Development" || entries[0].ProjectInfo.Task.Name != "Backend" {
+		t.Fatalf("Entries() = %v, want two stored entries with project info", entries)
+	}
+
+	// overlapping request is rejected as a whole
+	resp = request(t, http.MethodPost, store, map[string][]StoreEntry{"entries": {
+		{EmployeeId: 12, Date: "2025-03-11", Start: "08:00", End: "12:00"},
+		{EmployeeId: 12, Date: "2025-03-10", Start: "11:00", End: "13:00"},
+	}})
+	if resp.StatusCode != http.StatusBadRequest || len(fake.Entries()) != 2 {
+		t.Errorf("POST store with overlap = %d and %d entries, want 400 and 2 entries", resp.StatusCode, len(fake.Entries()))
+	}
+
+	deleteUrl := server.URL + BasePath + "/time_tracking/clock_entries/delete"
+	if resp := request(t, http.MethodPost, deleteUrl, map[string][]int{"clockEntryIds": {entries[0].Id, 99}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("POST delete with unknown ID = %d, want 404", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodPost, deleteUrl, map[string][]int{"clockEntryIds": {entries[0].Id}}); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("POST delete = %d, want 204", resp.StatusCode)
+	}
+	if remaining := fake.Entries(); len(remaining) != 1 || remaining[0].Id != entries[1].Id {
+		t.Errorf("Entries() = %v, want only the second entry", remaining)
+	}
+}
+
+func TestClockInAndOut(t *testing.T) {
+	fake, server := newTestServer(t, Seed{})
+	clockIn := server.URL + BasePath + "/time_tracking/employees/12/clock_in"
+	clockOut := server.URL + BasePath + "/time_tracking/employees/12/clock_out"
+
+	if resp := request(t, http.MethodPost, clockOut, ClockBody{Date: "2025-03-10", End: "16:00"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST clock_out before clock in = %d, want 400", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodPost, clockIn, ClockBody{Date: "2025-03-10", Start: "08:00"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST clock_in = %d, want 200", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodPost, clockIn, ClockBody{Date: "2025-03-10", Start: "09:00"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST clock_in twice = %d, want 400", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodPost, clockOut, ClockBody{Date: "2025-03-10", End: "12:15"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST clock_out = %d, want 200", resp.StatusCode)
+	}
+
+	entries := fake.Entries()
+	if len(entries) != 1 || entries[0].End == nil || entries[0].Hours != 4.25 {
+		t.Errorf("Entries() = %v, want closed entry of 4.25 hours", entries)
+	}
+}
+
+func TestWhosOut(t *testing.T) {
+	_, server := newTestServer(t, Seed{TimeOff: []TimeOff{
+		{Id: 1, Type: "Vacation", EmployeeId: 12, Start: "2025-03-07", End: "2025-03-11"},
+		{Id: 2, Type: "Sick", EmployeeId: 34, Start: "2025-03-20", End: "2025-03-20"},
+		{Id: 3, Name: "Company Day", Start: "2025-03-14", End: "2025-03-14"},
+	}})
+
+	resp := request(t, http.MethodGet, server.URL+BasePath+"/time_off/whos_out?start=2025-03-10&end=2025-03-14", nil)
+
+	var timeOff []TimeOff
+	json.NewDecoder(resp.Body).Decode(&timeOff)
+	if resp.StatusCode != http.StatusOK || len(timeOff) != 2 || timeOff[0].Id != 1 || timeOff[1].Type != "holiday" {
+		t.Errorf("GET whos_out = %d %v, want time off and company holiday overlapping the range", resp.StatusCode, timeOff)
+	}
+}
+
+func TestApiKey(t *testing.T) {
+	_, server := newTestServer(t, Seed{ApiKey: "secret"})
+
+	resp := request(t, http.MethodGet, server.URL+BasePath+"/time_tracking/employees/12/projects", nil)
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET projects with invalid API key = %d, want 401", resp.StatusCode)
+	}
+}
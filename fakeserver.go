@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/mluksic/bamboo/fakebamboo"
+)
+
+// processFakeServer starts in-memory BambooHR API for local testing, seeded from the optional JSON file
+func processFakeServer(addr string, seedPath string) {
+	var seed fakebamboo.Seed
+	if seedPath != "" {
+		var err error
+		seed, err = fakebamboo.LoadSeed(seedPath)
+		if err != nil {
+			fmt.Printf("%v. Aborting \n", err)
+			os.Exit(1)
+		}
+	}
+	server, err := fakebamboo.New(seed)
+	if err != nil {
+		fmt.Printf("Invalid seed: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Fake BambooHR is listening, use '--baseUrl http://%s%s' to point the app to it \n", addr, fakebamboo.BasePath)
+	if err := http.ListenAndServe(addr, server); err != nil {
+		fmt.Printf("Unable to start server: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+}
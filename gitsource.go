@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// shorter days, eg. with a single commit, are extended to 8 hours of work and the lunch break
+const minCommitDay = 8*time.Hour + 30*time.Minute
+
+// GitBlockSource derives work blocks from author's commit timestamps in local git repositories
+type GitBlockSource struct {
+	// commit timestamps grouped by date in employee's time zone
+	commits map[string][]time.Time
+	padding time.Duration
+}
+
+func NewGitBlockSource(repos []string, author string, start time.Time, end time.Time, padding time.Duration) (*GitBlockSource, error) {
+	if len(repos) == 0 {
+		return nil, errors.New("no git repositories provided")
+	}
+	if author == "" {
+		return nil, errors.New("git author email is required")
+	}
+
+	commits := make(map[string][]time.Time)
+	for _, repo := range repos {
+		timestamps, err := readGitCommits(repo, author, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range timestamps {
+			date := t.Format("2006-01-02")
+			commits[date] = append(commits[date], t)
+		}
+	}
+
+	return &GitBlockSource{
+		commits: commits,
+		padding: padding,
+	}, nil
+}
+
+func (g *GitBlockSource) workBlocks(date time.Time) []WorkBlock {
+	return commitBlocks(g.commits[date.Format("2006-01-02")], g.padding)
+}
+
+// readGitCommits returns author's commit timestamps between start (inclusive) and end (exclusive) date
+func readGitCommits(repo string, author string, start time.Time, end time.Time) ([]time.Time, error) {
+	since := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+	until := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, location)
+
+	cmd := exec.Command("git", "-C", repo, "log", "--all", "--author="+author, "--format=%aI",
+		"--since="+since.Format(time.RFC3339), "--until="+until.Format(time.RFC3339))
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, errors.New(fmt.Sprintf("unable to read git log of '%s': %s", repo, strings.TrimSpace(string(exitErr.Stderr))))
+		}
+		return nil, errors.New(fmt.Sprintf("unable to run git: %v", err))
+	}
+
+	timestamps, err := parseCommitTimestamps(string(output))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to parse git log of '%s': %v", repo, err))
+	}
+
+	// git filters by committer date, so rebased commits have to be filtered by author date again
+	var inRange []time.Time
+	for _, t := range timestamps {
+		if !t.Before(since) && t.Before(until) {
+			inRange = append(inRange, t)
+		}
+	}
+
+	return inRange, nil
+}
+
+// parseCommitTimestamps parses one ISO 8601 timestamp per line and converts it to employee's time zone
+func parseCommitTimestamps(output string) ([]time.Time, error) {
+	var timestamps []time.Time
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, line)
+		if err != nil {
+			return nil, err
+		}
+		timestamps = append(timestamps, t.In(location))
+	}
+
+	return timestamps, nil
+}
+
+// commitBlocks builds the day's work blocks from the first to the last commit, extended by padding on both
+// sides. Days are split by a 30min lunch break in the longest gap between commits, and short days are extended to
+// the full working day
+func commitBlocks(commits []time.Time, padding time.Duration) []WorkBlock {
+	if len(commits) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(commits)
+	slices.SortFunc(sorted, func(a, b time.Time) int { return a.Compare(b) })
+
+	first, last := sorted[0], sorted[len(sorted)-1]
+	midnight := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	// keep the whole day within the date
+	dayEnd := midnight.AddDate(0, 0, 1).Add(-time.Minute)
+	start := maxTime(first.Add(-padding), midnight).Truncate(time.Minute)
+	end := minTime(last.Add(padding), dayEnd).Truncate(time.Minute)
+
+	// extend short days after the last commit, or before the first one when the day would end after midnight
+	if end.Sub(start) < minCommitDay {
+		end = minTime(start.Add(minCommitDay), dayEnd)
+		start = end.Add(-minCommitDay)
+	}
+
+	// place lunch in the middle of the longest gap between commits, or in the middle of the day
+	breakStart := start.Add(end.Sub(start) / 2)
+	longestGap := time.Duration(0)
+	for i := 1; i < len(sorted); i++ {
+		if gap := sorted[i].Sub(sorted[i-1]); gap > longestGap && gap >= 30*time.Minute {
+			longestGap = gap
+			breakStart = sorted[i-1].Add(gap/2 - 15*time.Minute)
+		}
+	}
+	breakStart = breakStart.Truncate(time.Minute)
+	breakEnd := breakStart.Add(30 * time.Minute)
+
+	return []WorkBlock{
+		{start: start, end: breakStart},
+		{start: breakEnd, end: end},
+	}
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCommitBlocks(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", "2025-03-13 "+clock)
+		return t
+	}
+
+	tests := []struct {
+		name    string
+		commits []time.Time
+		want    []WorkBlock
+	}{
+		{
+			"NoCommits",
+			nil,
+			nil,
+		},
+		{
+			"SingleCommit",
+			[]time.Time{at("10:00")},
+			[]WorkBlock{
+				{start: at("09:30"), end: at("13:45")},
+				{start: at("14:15"), end: at("18:00")},
+			},
+		},
+		{
+			"ShortDay",
+			[]time.Time{at("14:10"), at("10:05")},
+			[]WorkBlock{
+				{start: at("09:35"), end: at("11:52")},
+				{start: at("12:22"), end: at("18:05")},
+			},
+		},
+		{
+			"LunchInLongestGap",
+			[]time.Time{at("08:40"), at("10:00"), at("11:20"), at("13:20"), at("14:30"), at("16:00"), at("17:00")},
+			[]WorkBlock{
+				{start: at("08:10"), end: at("12:05")},
+				{start: at("12:35"), end: at("17:30")},
+			},
+		},
+		{
+			"PaddingWithinDate",
+			[]time.Time{at("00:10"), at("07:00")},
+			[]WorkBlock{
+				{start: at("00:00"), end: at("03:20")},
+				{start: at("03:50"), end: at("08:30")},
+			},
+		},
+		{
+			"LateSingleCommit",
+			[]time.Time{at("23:00")},
+			[]WorkBlock{
+				{start: at("15:29"), end: at("19:44")},
+				{start: at("20:14"), end: at("23:59")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := commitBlocks(test.commits, 30*time.Minute)
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("commitBlocks() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseCommitTimestamps(t *testing.T) {
+	original := location
+	location = time.UTC
+	t.Cleanup(func() { location = original })
+
+	got, err := parseCommitTimestamps("2025-03-13T09:15:00+01:00\n\n2025-03-13T17:45:10+01:00\n")
+	if err != nil {
+		t.Fatalf("parseCommitTimestamps() error = %v", err)
+	}
+	want := []time.Time{
+		time.Date(2025, time.March, 13, 8, 15, 0, 0, time.UTC),
+		time.Date(2025, time.March, 13, 16, 45, 10, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("parseCommitTimestamps() = %v, want %v", got, want)
+	}
+
+	if _, err := parseCommitTimestamps("yesterday"); err == nil {
+		t.Errorf("parseCommitTimestamps() should return error for invalid timestamp")
+	}
+}
+
+func TestGitBlockSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	original := location
+	location = time.UTC
+	t.Cleanup(func() { location = original })
+
+	repo := t.TempDir()
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=Jane", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE="+date,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v %s", args, err, output)
+		}
+	}
+	git("2025-03-13T09:00:00Z", "init", "-q")
+	git("2025-03-13T09:00:00Z", "commit", "-q", "--allow-empty", "-m", "first")
+	git("2025-03-13T11:00:00Z", "commit", "-q", "--allow-empty", "-m", "second")
+	git("2025-03-17T10:00:00Z", "commit", "-q", "--allow-empty", "-m", "outside of range")
+
+	start := time.Date(2025, time.March, 13, 0, 0, 0, 0, time.UTC)
+	source, err := NewGitBlockSource([]string{repo}, "jane@example.com", start, start.AddDate(0, 0, 2), 30*time.Minute)
+	if err != nil {
+		t.Fatalf("NewGitBlockSource() error = %v", err)
+	}
+
+	// the day is extended to the full working day, with lunch between the commits
+	want := []WorkBlock{
+		{start: start.Add(8*time.Hour + 30*time.Minute), end: start.Add(9*time.Hour + 45*time.Minute)},
+		{start: start.Add(10*time.Hour + 15*time.Minute), end: start.Add(17 * time.Hour)},
+	}
+	if got := source.workBlocks(start); !reflect.DeepEqual(want, got) {
+		t.Errorf("workBlocks(2025-03-13) = %v, want %v", got, want)
+	}
+	if got := source.workBlocks(start.AddDate(0, 0, 1)); got != nil {
+		t.Errorf("workBlocks(2025-03-14) should be empty, got %v", got)
+	}
+	if len(source.commits) != 1 {
+		t.Errorf("NewGitBlockSource() should skip commits outside of the range, got %v", source.commits)
+	}
+}
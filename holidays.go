@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"embed"
 	"encoding/csv"
 	"encoding/json"
@@ -17,6 +18,12 @@ import (
 //go:embed slovenian_public_work_off_days.csv
 var holidayFile embed.FS
 
+// timeOffDay marks employee's time off among public holidays
+const timeOffDay = "timeOff"
+
+// whosOutHoliday is the who's out type of company holidays, which aren't employee's time off
+const whosOutHoliday = "holiday"
+
 type HolidayFetcher interface {
 	loadHolidays() (map[string]string, error)
 	fetchTimeOff() (map[string]string, error)
@@ -33,61 +40,137 @@ func NewCsvHolidays(filepath string) *CsvHolidayFetcher {
 }
 
 func (h *CsvHolidayFetcher) loadHolidays() (map[string]string, error) {
-	file, err := holidayFile.Open(h.filepath)
+	publicHolidays, timeOff, err := h.loadDaysOff()
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("unable to open file: %v \n", err))
+		return nil, err
 	}
 
-	defer file.Close()
-
-	r := csv.NewReader(file)
-	r.Comma = ';'
+	return combineDaysOff(publicHolidays, timeOff), nil
+}
 
+// loadDaysOff returns public holidays and employee's time off separately, so their sources can be told apart.
+// Company holidays from BambooHR are returned among public holidays
+func (h *CsvHolidayFetcher) loadDaysOff() (map[string]string, map[string]string, error) {
 	var timeOffs = make(map[string]string)
+	var companyHolidays map[string]string
 	// skip fetching time offs if employeeID is not set
 	if employeeId > 0 {
-		timeOffs, err = h.fetchTimeOff()
+		team, holidays, err := h.fetchTeamTimeOff()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		if outDays, ok := team[employeeId]; ok {
+			timeOffs = outDays
+		}
+		companyHolidays = holidays
+	}
+	holidays, err := h.loadPublicHolidays()
+	if err != nil {
+		return nil, nil, err
 	}
-	holidays, err := h.readHolidaysFile(r)
+
+	return combineDaysOff(holidays, companyHolidays), timeOffs, nil
+}
+
+// requiredHolidays returns public holidays and company holidays from BambooHR between start and end date, which are
+// the same for every employee, so employee's time off isn't included. Company holidays are fetched only when
+// 'apiKey' is set
+func requiredHolidays(start time.Time, end time.Time) (map[string]string, error) {
+	h := NewCsvHolidays("slovenian_public_work_off_days.csv")
+	public, err := h.loadPublicHolidays()
 	if err != nil {
 		return nil, err
 	}
+	if apiKey == "" {
+		return public, nil
+	}
 
-	// combine public holidays and time offs
-	outs := make(map[string]string)
-	if timeOffs != nil {
-		for k, v := range timeOffs {
-			outs[k] = v
-		}
+	startDate, endDate = start.Format("2006-01-02"), end.Format("2006-01-02")
+	_, company, err := h.fetchTeamTimeOff()
+	if err != nil {
+		return nil, err
 	}
 
+	return combineDaysOff(public, company), nil
+}
+
+// loadPublicHolidays reads public holidays from the embedded file
+func (h *CsvHolidayFetcher) loadPublicHolidays() (map[string]string, error) {
+	file, err := holidayFile.Open(h.filepath)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to open file: %v \n", err))
+	}
+
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.Comma = ';'
+
+	return h.readHolidaysFile(r)
+}
+
+// combineDaysOff combines public holidays and time offs, holiday names take precedence
+func combineDaysOff(holidays map[string]string, timeOffs map[string]string) map[string]string {
+	outs := make(map[string]string)
+	for k, v := range timeOffs {
+		outs[k] = v
+	}
 	for k, v := range holidays {
 		outs[k] = v
 	}
 
-	return outs, nil
+	return outs
 }
 
 func (h *CsvHolidayFetcher) fetchTimeOff() (map[string]string, error) {
-	var urlTemplate = "https://%s:x@api.bamboohr.com/api/gateway.php/flaviar/v1/time_off/whos_out?start=%s&end=%s"
-	url := fmt.Sprintf(urlTemplate, apiKey, startDate, endDate)
-
-	req, err := http.NewRequest("GET", url, nil)
+	team, _, err := h.fetchTeamTimeOff()
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("unable to create whos out request: %v \n", err))
+		return nil, err
+	}
+	if outDays, ok := team[employeeId]; ok {
+		return outDays, nil
 	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
 
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	return make(map[string]string), nil
+}
+
+// fetchTeamTimeOff returns time off days of every employee who is out between start and end date, and company
+// holidays of the range
+func (h *CsvHolidayFetcher) fetchTeamTimeOff() (map[int]map[string]string, map[string]string, error) {
+	url := apiUrl("/time_off/whos_out?start=%s&end=%s", startDate, endDate)
+
+	body, err := cachedFetch(cacheKey("whos_out", startDate, endDate), func() ([]byte, error) {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to create whos out request: %v \n", err))
+		}
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("Accept", "application/json")
+
+		httpClient := &http.Client{}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to fetch whos out: %v \n", err))
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to read body: %v \n", err))
+		}
+		// error responses mustn't be cached
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized). Aborting")
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, errors.New(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		}
+
+		return body, nil
+	})
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("unable to fetch whos out: %v \n", err))
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
 
 	var resJson []struct {
 		Id         int    `json:"id"`
@@ -97,43 +180,48 @@ func (h *CsvHolidayFetcher) fetchTimeOff() (map[string]string, error) {
 		Start      string `json:"start"`
 		End        string `json:"end"`
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("unable to read body: %v \n", err))
-	}
 	if err := json.Unmarshal(body, &resJson); err != nil {
-		return nil, errors.New(fmt.Sprintf("unable to marshal response: %v \n", err))
+		return nil, nil, errors.New(fmt.Sprintf("unable to marshal response: %v \n", err))
 	}
 
-	outDays := make(map[string]string)
+	team := make(map[int]map[string]string)
+	holidays := make(map[string]string)
 	for _, outEntry := range resJson {
-		if outEntry.EmployeeId != employeeId {
+		// company holidays apply to everyone and aren't time off, even when they're assigned to employee
+		isHoliday := outEntry.Type == whosOutHoliday
+		if !isHoliday && outEntry.EmployeeId == 0 {
 			continue
 		}
 
 		entryStart, err := time.Parse("2006-01-02", outEntry.Start)
 		if err != nil {
-			return nil, errors.New(fmt.Sprintf("unable to parse start time: %v \n", err))
+			return nil, nil, errors.New(fmt.Sprintf("unable to parse start time: %v \n", err))
 		}
 		entryEnd, err := time.Parse("2006-01-02", outEntry.End)
 		if err != nil {
-			return nil, errors.New(fmt.Sprintf("unable to parse end time: %v \n", err))
+			return nil, nil, errors.New(fmt.Sprintf("unable to parse end time: %v \n", err))
 		}
 
-		if entryEnd == entryStart {
-			outDays[outEntry.Start] = "timeOff"
-		}
 		if entryStart.After(entryEnd) {
-			return nil, errors.New(fmt.Sprintf("entry start %s should not be after entry end %s \n", outEntry.Start, outEntry.End))
+			return nil, nil, errors.New(fmt.Sprintf("entry start %s should not be after entry end %s \n", outEntry.Start, outEntry.End))
 		}
 
+		outDays, kind := holidays, cmp.Or(outEntry.Name, whosOutHoliday)
+		if !isHoliday {
+			outDays = team[outEntry.EmployeeId]
+			if outDays == nil {
+				outDays = make(map[string]string)
+				team[outEntry.EmployeeId] = outDays
+			}
+			// keep the type of time off, so it can be explained by the 'why' command
+			kind = cmp.Or(outEntry.Type, timeOffDay)
+		}
 		for d := entryStart; !d.After(entryEnd); d = d.AddDate(0, 0, 1) {
-			outDays[d.Format("2006-01-02")] = "timeOff"
+			outDays[d.Format("2006-01-02")] = kind
 		}
 	}
 
-	return outDays, nil
+	return team, holidays, nil
 }
 
 func (h *CsvHolidayFetcher) readHolidaysFile(r *csv.Reader) (map[string]string, error) {
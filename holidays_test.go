@@ -2,9 +2,12 @@ package main
 
 import (
 	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestReadHolidaysFile(t *testing.T) {
@@ -99,3 +102,76 @@ func TestLoadExcludedDays(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchTeamTimeOffCompanyHolidays(t *testing.T) {
+	useCacheDir(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": 1, "type": "timeOff", "employeeId": 12, "name": "Jane Doe", "start": "2025-03-10", "end": "2025-03-11"},
+			{"id": 2, "type": "holiday", "name": "Company Day", "start": "2025-03-12", "end": "2025-03-12"},
+			{"id": 3, "type": "holiday", "employeeId": 12, "name": "Team Offsite", "start": "2025-03-13", "end": "2025-03-14"}
+		]`))
+	}))
+	defer server.Close()
+	originalBaseUrl, originalStart, originalEnd, originalEmployeeId := baseUrl, startDate, endDate, employeeId
+	baseUrl, startDate, endDate, employeeId = server.URL, "2025-03-01", "2025-04-01", 12
+	t.Cleanup(func() {
+		baseUrl, startDate, endDate, employeeId = originalBaseUrl, originalStart, originalEnd, originalEmployeeId
+	})
+
+	team, holidays, err := NewCsvHolidays("").fetchTeamTimeOff()
+	if err != nil {
+		t.Fatalf("fetchTeamTimeOff() error = %v", err)
+	}
+
+	wantTeam := map[int]map[string]string{12: {"2025-03-10": "timeOff", "2025-03-11": "timeOff"}}
+	if !reflect.DeepEqual(wantTeam, team) {
+		t.Errorf("fetchTeamTimeOff() team = %v, want %v", team, wantTeam)
+	}
+	wantHolidays := map[string]string{"2025-03-12": "Company Day", "2025-03-13": "Team Offsite", "2025-03-14": "Team Offsite"}
+	if !reflect.DeepEqual(wantHolidays, holidays) {
+		t.Errorf("fetchTeamTimeOff() holidays = %v, want %v", holidays, wantHolidays)
+	}
+
+	publicHolidays, timeOff, err := NewCsvHolidays("slovenian_public_work_off_days.csv").loadDaysOff()
+	if err != nil {
+		t.Fatalf("loadDaysOff() error = %v", err)
+	}
+	if publicHolidays["2025-03-13"] != "Team Offsite" || timeOff["2025-03-13"] != "" {
+		t.Errorf("loadDaysOff() company holiday should be among public holidays, got holiday %q and time off %q", publicHolidays["2025-03-13"], timeOff["2025-03-13"])
+	}
+}
+
+func TestRequiredHolidays(t *testing.T) {
+	useCacheDir(t)
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[
+			{"id": 1, "type": "timeOff", "employeeId": 12, "name": "Jane Doe", "start": "2025-01-06", "end": "2025-01-07"},
+			{"id": 2, "type": "holiday", "name": "Company Day", "start": "2025-01-10", "end": "2025-01-10"}
+		]`))
+	}))
+	defer server.Close()
+	originalBaseUrl, originalApiKey, originalStart, originalEnd, originalEmployeeId := baseUrl, apiKey, startDate, endDate, employeeId
+	baseUrl, apiKey, startDate, endDate, employeeId = server.URL, "key", "2024-01-01", "2025-12-31", 12
+	t.Cleanup(func() {
+		baseUrl, apiKey, startDate, endDate, employeeId = originalBaseUrl, originalApiKey, originalStart, originalEnd, originalEmployeeId
+	})
+
+	start, end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	got, err := requiredHolidays(start, end)
+	if err != nil {
+		t.Fatalf("requiredHolidays() error = %v", err)
+	}
+
+	if !strings.Contains(query, "start=2025-01-01") || !strings.Contains(query, "end=2025-02-01") {
+		t.Errorf("requiredHolidays() should fetch who's out of the range, got query %q", query)
+	}
+	if got["2025-01-01"] == "" || got["2025-01-10"] != "Company Day" {
+		t.Errorf("requiredHolidays() should include public and company holidays, got %v", got)
+	}
+	if _, ok := got["2025-01-06"]; ok {
+		t.Errorf("requiredHolidays() shouldn't include employee's time off, got %v", got)
+	}
+}
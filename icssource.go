@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarEvent is a single occurrence of a VEVENT from iCalendar file
+type calendarEvent struct {
+	uid     string
+	summary string
+	start   time.Time
+	end     time.Time
+	// busy events (meetings, focus blocks) are evidence of work, free and cancelled ones aren't
+	busy   bool
+	allDay bool
+	rrule  string
+	exdate []time.Time
+	// recurrenceId is set on events that override a single occurrence of the recurring event
+	recurrenceId time.Time
+}
+
+// IcsBlockSource derives work blocks from busy events in iCalendar file
+type IcsBlockSource struct {
+	// busy events grouped by date in employee's time zone
+	events map[string][]calendarEvent
+	notes  bool
+	r      *rand.Rand
+}
+
+func NewIcsBlockSource(path string, start time.Time, end time.Time, notes bool) (*IcsBlockSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to open calendar file: %v", err))
+	}
+	defer file.Close()
+
+	events, err := parseCalendar(file)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, location)
+
+	days := make(map[string][]calendarEvent)
+	for _, event := range expandEvents(events, from, to) {
+		if !event.busy || event.allDay {
+			continue
+		}
+		date := event.start.Format("2006-01-02")
+		days[date] = append(days[date], event)
+	}
+
+	return &IcsBlockSource{
+		events: days,
+		notes:  notes,
+		r:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func (c *IcsBlockSource) workBlocks(date time.Time) []WorkBlock {
+	events := c.events[date.Format("2006-01-02")]
+	if len(events) == 0 {
+		return nil
+	}
+
+	blocks := eventBlocks(events, templateBlocks(date, c.r))
+	if c.notes {
+		for i := range blocks {
+			blocks[i].note = eventTitles(events, blocks[i])
+		}
+	}
+
+	return blocks
+}
+
+// eventBlocks places the templated day, so it covers all busy events of the day, and moves the lunch break
+// into the free gap closest to the middle of the day
+func eventBlocks(events []calendarEvent, template []WorkBlock) []WorkBlock {
+	sorted := slices.Clone(events)
+	slices.SortFunc(sorted, func(a, b calendarEvent) int { return a.start.Compare(b.start) })
+
+	busyStart, busyEnd := sorted[0].start, sorted[0].end
+	for _, event := range sorted {
+		busyEnd = maxTime(busyEnd, event.end)
+	}
+
+	start := template[0].start
+	length := template[len(template)-1].end.Sub(start)
+	// day has to start before the first event and end after the last one
+	if start.After(busyStart) {
+		start = busyStart
+	}
+	end := start.Add(length)
+	// move the day later, so it still ends after the last event
+	if end.Before(busyEnd) {
+		end = busyEnd
+		start = minTime(end.Add(-length), busyStart)
+	}
+
+	breakStart, ok := freeGap(sorted, start, end, 30*time.Minute)
+	if !ok {
+		return []WorkBlock{{start: start, end: end}}
+	}
+	breakEnd := breakStart.Add(30 * time.Minute)
+
+	return []WorkBlock{
+		{start: start, end: breakStart},
+		{start: breakEnd, end: end},
+	}
+}
+
+// freeGap finds start of the free interval of given length between start and end, closest to the middle
+// of the day. Day's edges aren't considered, so the break is always surrounded by work
+func freeGap(events []calendarEvent, start time.Time, end time.Time, length time.Duration) (time.Time, bool) {
+	middle := start.Add(end.Sub(start) / 2).Add(-length / 2).Truncate(time.Minute)
+
+	var best time.Time
+	found := false
+	consider := func(gapStart time.Time, gapEnd time.Time) {
+		gapStart = maxTime(gapStart, start.Add(time.Minute))
+		gapEnd = minTime(gapEnd, end.Add(-time.Minute))
+		if gapEnd.Sub(gapStart) < length {
+			return
+		}
+		// position closest to the middle of the day within the gap
+		candidate := minTime(maxTime(middle, gapStart), gapEnd.Add(-length)).Truncate(time.Minute)
+		if candidate.Before(gapStart) {
+			candidate = candidate.Add(time.Minute)
+		}
+		if !found || candidate.Sub(middle).Abs() < best.Sub(middle).Abs() {
+			best, found = candidate, true
+		}
+	}
+
+	cursor := start
+	for _, event := range events {
+		if event.start.After(cursor) {
+			consider(cursor, event.start)
+		}
+		cursor = maxTime(cursor, event.end)
+	}
+	consider(cursor, end)
+
+	return best, found
+}
+
+// eventTitles returns titles of events overlapping with the block
+func eventTitles(events []calendarEvent, block WorkBlock) string {
+	var titles []string
+	for _, event := range events {
+		if event.summary == "" || !event.start.Before(block.end) || !event.end.After(block.start) {
+			continue
+		}
+		if !slices.Contains(titles, event.summary) {
+			titles = append(titles, event.summary)
+		}
+	}
+
+	return strings.Join(titles, "; ")
+}
+
+// parseCalendar reads VEVENTs from iCalendar file
+func parseCalendar(r io.Reader) ([]calendarEvent, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []calendarEvent
+	var event *calendarEvent
+	var duration time.Duration
+	// depth of components nested in the event, eg. VALARM
+	nested := 0
+	for _, line := range lines {
+		name, params, value := parseContentLine(line)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &calendarEvent{busy: true}
+			duration = 0
+			nested = 0
+			continue
+		case name == "BEGIN" && event != nil:
+			nested++
+			continue
+		case name == "END" && value != "VEVENT" && nested > 0:
+			nested--
+			continue
+		case name == "END" && value == "VEVENT" && event != nil:
+			if event.end.IsZero() {
+				event.end = event.start.Add(duration)
+			}
+			if event.start.IsZero() {
+				return nil, errors.New(fmt.Sprintf("event '%s' is missing DTSTART", event.summary))
+			}
+			events = append(events, *event)
+			event = nil
+			continue
+		}
+		if event == nil || nested > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.uid = value
+		case "SUMMARY":
+			event.summary = unescapeText(value)
+		case "DTSTART":
+			event.start, event.allDay, err = parseCalendarTime(value, params)
+		case "DTEND":
+			event.end, _, err = parseCalendarTime(value, params)
+		case "DURATION":
+			duration, err = parseCalendarDuration(value)
+		case "TRANSP":
+			event.busy = event.busy && value != "TRANSPARENT"
+		case "STATUS":
+			event.busy = event.busy && value != "CANCELLED"
+		case "RRULE":
+			event.rrule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, _, parseErr := parseCalendarTime(v, params)
+				if parseErr != nil {
+					err = parseErr
+					break
+				}
+				event.exdate = append(event.exdate, t)
+			}
+		case "RECURRENCE-ID":
+			event.recurrenceId, _, err = parseCalendarTime(value, params)
+		}
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to parse %s of event '%s': %v", name, event.summary, err))
+		}
+	}
+
+	return events, nil
+}
+
+// unfoldLines joins long lines, which are split into multiple lines starting with whitespace
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to read calendar file: %v", err))
+	}
+
+	return lines, nil
+}
+
+// parseContentLine splits 'NAME;PARAM=value:VALUE' line into its parts
+func parseContentLine(line string) (string, map[string]string, string) {
+	// find the first colon, which isn't within quoted parameter value
+	quoted := false
+	split := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		}
+		if c == ':' && !quoted {
+			split = i
+			break
+		}
+	}
+	if split == -1 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	parts := strings.Split(line[:split], ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, line[split+1:]
+}
+
+// parseCalendarTime parses UTC, zoned or floating date-time, or all-day date, converted to employee's time zone
+func parseCalendarTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, location)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.In(location), false, err
+	}
+
+	loc := location
+	if tzid, ok := params["TZID"]; ok {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+
+	return t, false, err
+}
+
+var calendarDurationRegexp = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseCalendarDuration parses duration like PT1H30M or P1D
+func parseCalendarDuration(value string) (time.Duration, error) {
+	match := calendarDurationRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.New(fmt.Sprintf("invalid duration '%s'", value))
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(match[i+2])
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// expandEvents expands recurring events into occurrences between from and to, replacing occurrences which
+// were moved or changed, and converts them to employee's time zone
+func expandEvents(events []calendarEvent, from time.Time, to time.Time) []calendarEvent {
+	overridden := make(map[string][]time.Time)
+	for _, event := range events {
+		if !event.recurrenceId.IsZero() {
+			overridden[event.uid] = append(overridden[event.uid], event.recurrenceId)
+		}
+	}
+
+	var occurrences []calendarEvent
+	for _, event := range events {
+		for _, occurrence := range eventOccurrences(event, to) {
+			if slices.ContainsFunc(event.exdate, occurrence.start.Equal) {
+				continue
+			}
+			if event.recurrenceId.IsZero() && slices.ContainsFunc(overridden[event.uid], occurrence.start.Equal) {
+				continue
+			}
+			if !occurrence.end.After(from) || !occurrence.start.Before(to) {
+				continue
+			}
+			occurrence.start = occurrence.start.In(location)
+			occurrence.end = occurrence.end.In(location)
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	return occurrences
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// eventOccurrences returns occurrences of the event starting before 'to'. Daily and weekly rules are
+// supported, other recurring events only have their first occurrence
+func eventOccurrences(event calendarEvent, to time.Time) []calendarEvent {
+	if event.rrule == "" {
+		return []calendarEvent{event}
+	}
+
+	rule := make(map[string]string)
+	for _, part := range strings.Split(event.rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		rule[strings.ToUpper(key)] = value
+	}
+
+	interval, err := strconv.Atoi(rule["INTERVAL"])
+	if err != nil || interval < 1 {
+		interval = 1
+	}
+	count, _ := strconv.Atoi(rule["COUNT"])
+	until := to
+	if value, ok := rule["UNTIL"]; ok {
+		if t, _, err := parseCalendarTime(value, nil); err == nil && t.Before(until) {
+			// UNTIL is inclusive
+			until = t.Add(time.Second)
+		}
+	}
+
+	var days []time.Weekday
+	for _, day := range strings.Split(rule["BYDAY"], ",") {
+		if wd, ok := weekdays[strings.ToUpper(day)]; ok {
+			days = append(days, wd)
+		}
+	}
+
+	length := event.end.Sub(event.start)
+	var occurrences []calendarEvent
+	add := func(start time.Time) bool {
+		if !start.Before(until) || (count > 0 && len(occurrences) >= count) {
+			return false
+		}
+		occurrence := event
+		occurrence.start = start
+		occurrence.end = start.Add(length)
+		occurrences = append(occurrences, occurrence)
+		return true
+	}
+
+	switch rule["FREQ"] {
+	case "DAILY":
+		// AddDate keeps the wall clock time across DST transitions
+		for i := 0; add(event.start.AddDate(0, 0, i*interval)); i++ {
+		}
+	case "WEEKLY":
+		if len(days) == 0 {
+			days = []time.Weekday{event.start.Weekday()}
+		}
+		weekStart := event.start.AddDate(0, 0, -((int(event.start.Weekday()) + 6) % 7))
+		for week := 0; ; week += interval {
+			monday := weekStart.AddDate(0, 0, week*7)
+			if !monday.Before(until) {
+				break
+			}
+			stop := false
+			for offset := 0; offset < 7 && !stop; offset++ {
+				start := monday.AddDate(0, 0, offset)
+				if start.Before(event.start) || !slices.Contains(days, start.Weekday()) {
+					continue
+				}
+				stop = !add(start)
+			}
+			if stop {
+				break
+			}
+		}
+	default:
+		add(event.start)
+	}
+
+	return occurrences
+}
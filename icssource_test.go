@@ -0,0 +1,181 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Daily standup
+DTSTART;TZID=Europe/Ljubljana:20250310T093000
+DTEND;TZID=Europe/Ljubljana:20250310T094500
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=6
+EXDATE;TZID=Europe/Ljubljana:20250312T093000
+BEGIN:VALARM
+TRIGGER:-PT10M
+DURATION:PT5M
+SUMMARY:Reminder
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Ljubljana:20250314T093000
+SUMMARY:Daily standup
+DTSTART;TZID=Europe/Ljubljana:20250314T110000
+DTEND;TZID=Europe/Ljubljana:20250314T111500
+END:VEVENT
+BEGIN:VEVENT
+UID:planning
+SUMMARY:Sprint planning\, Q1
+  roadmap
+DTSTART:20250314T150000Z
+DURATION:PT1H30M
+END:VEVENT
+BEGIN:VEVENT
+UID:lunch
+SUMMARY:Lunch
+DTSTART;TZID=Europe/Ljubljana:20250314T120000
+DTEND;TZID=Europe/Ljubljana:20250314T130000
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+SUMMARY:Vacation
+DTSTART;VALUE=DATE:20250313
+DTEND;VALUE=DATE:20250314
+END:VEVENT
+END:VCALENDAR
+`
+
+func useLjubljana(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	if err != nil {
+		t.Fatalf("unable to load time zone: %v", err)
+	}
+	original := location
+	location = loc
+	t.Cleanup(func() { location = original })
+
+	return loc
+}
+
+func TestParseAndExpandCalendar(t *testing.T) {
+	loc := useLjubljana(t)
+	at := func(date string, clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+		return t
+	}
+
+	events, err := parseCalendar(strings.NewReader(testCalendar))
+	if err != nil {
+		t.Fatalf("parseCalendar() error = %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("parseCalendar() should return 5 events, got %d", len(events))
+	}
+
+	occurrences := expandEvents(events, at("2025-03-10", "00:00"), at("2025-03-17", "00:00"))
+
+	type occurrence struct {
+		summary    string
+		start, end time.Time
+		busy       bool
+		allDay     bool
+	}
+	var got []occurrence
+	for _, o := range occurrences {
+		got = append(got, occurrence{o.summary, o.start, o.end, o.busy, o.allDay})
+	}
+	want := []occurrence{
+		{"Daily standup", at("2025-03-10", "09:30"), at("2025-03-10", "09:45"), true, false},
+		{"Daily standup", at("2025-03-14", "11:00"), at("2025-03-14", "11:15"), true, false},
+		{"Sprint planning, Q1 roadmap", at("2025-03-14", "16:00"), at("2025-03-14", "17:30"), true, false},
+		{"Lunch", at("2025-03-14", "12:00"), at("2025-03-14", "13:00"), false, false},
+		{"Vacation", at("2025-03-13", "00:00"), at("2025-03-14", "00:00"), true, true},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expandEvents() = %v, want %v", got, want)
+	}
+}
+
+func TestEventBlocks(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", "2025-03-14 "+clock)
+		return t
+	}
+	template := []WorkBlock{
+		{start: at("08:30"), end: at("12:15")},
+		{start: at("12:45"), end: at("16:30")},
+	}
+
+	tests := []struct {
+		name   string
+		events []calendarEvent
+		want   []WorkBlock
+	}{
+		{
+			"BreakMovedToFreeGap",
+			[]calendarEvent{
+				{summary: "Design review", start: at("11:30"), end: at("13:00")},
+				{summary: "1:1", start: at("09:00"), end: at("09:30")},
+			},
+			[]WorkBlock{
+				{start: at("08:30"), end: at("13:00")},
+				{start: at("13:30"), end: at("16:30")},
+			},
+		},
+		{
+			"DayMovedToCoverLateEvent",
+			[]calendarEvent{
+				{summary: "Release", start: at("17:00"), end: at("18:00")},
+			},
+			[]WorkBlock{
+				{start: at("10:00"), end: at("13:45")},
+				{start: at("14:15"), end: at("18:00")},
+			},
+		},
+		{
+			"NoFreeGap",
+			[]calendarEvent{
+				{summary: "Workshop", start: at("08:00"), end: at("16:00")},
+			},
+			[]WorkBlock{
+				{start: at("08:00"), end: at("16:00")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := eventBlocks(test.events, template)
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("eventBlocks() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEventTitles(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("15:04", clock)
+		return t
+	}
+	events := []calendarEvent{
+		{summary: "Standup", start: at("09:30"), end: at("09:45")},
+		{summary: "Design review", start: at("11:30"), end: at("13:00")},
+		{summary: "Standup", start: at("10:30"), end: at("10:45")},
+	}
+
+	got := eventTitles(events, WorkBlock{start: at("08:30"), end: at("12:00")})
+
+	if got != "Standup; Design review" {
+		t.Errorf("eventTitles() = %q, want %q", got, "Standup; Design review")
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importEntries validates entries read from a file and pushes them to BambooHR
+func importEntries(report Report, entries []Entry, allowHolidays bool, force bool) {
+	entries, err := validateImportedEntries(entries, report, allowHolidays)
+	if err != nil {
+		fmt.Printf("Invalid import file: \n%v \n", err)
+		os.Exit(1)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("There are no entries left to import. Exiting the program...")
+		os.Exit(0)
+	}
+
+	submitEntries(entries, report, force)
+}
+
+// readImportFile reads entries from native CSV or JSON file, based on the file extension, or from CSV export
+// of a third-party time tracker. Entries are rounded to the nearest step in minutes, when it's set
+func readImportFile(path string, format string, step int) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to open file: %v", err))
+	}
+	defer file.Close()
+
+	var entries []Entry
+	switch {
+	case format != FormatBamboo:
+		entries, err = readTrackerEntries(file, format)
+	case strings.ToLower(filepath.Ext(path)) == ".csv":
+		entries, err = readCsvEntries(file)
+	case strings.ToLower(filepath.Ext(path)) == ".json":
+		entries, err = readJsonEntries(file)
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported file type '%s', use .csv or .json", filepath.Ext(path)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = roundEntries(entries, step)
+	if err != nil {
+		return nil, err
+	}
+	// time trackers split the day into many small segments, which are merged into continuous blocks
+	if format != FormatBamboo {
+		return mergeEntries(entries)
+	}
+
+	return entries, nil
+}
+
+// readCsvEntries reads entries from CSV with a header row. 'date', 'start' and 'end' columns are required,
+// 'note', 'projectId' and 'taskId' are optional
+func readCsvEntries(reader io.Reader) ([]Entry, error) {
+	table, err := readCsvTable(reader, ',', "date", "start", "end")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for i, row := range table.rows {
+		entry := Entry{
+			Date:  table.value(row, "date"),
+			Start: table.value(row, "start"),
+			End:   table.value(row, "end"),
+			Note:  table.value(row, "note"),
+		}
+		if value := table.value(row, "projectId"); value != "" {
+			entry.ProjectId, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("line %d: invalid projectId '%s'", table.line(i), value))
+			}
+		}
+		if value := table.value(row, "taskId"); value != "" {
+			entry.TaskId, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("line %d: invalid taskId '%s'", table.line(i), value))
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// csvTable is CSV file with columns looked up by their case-insensitive header names
+type csvTable struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+func readCsvTable(reader io.Reader, comma rune, required ...string) (*csvTable, error) {
+	r := csv.NewReader(reader)
+	r.Comma = comma
+	r.TrimLeadingSpace = true
+	// exports from some tools have rows with trailing empty columns
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to read header row: %v", err))
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		// strip UTF-8 BOM some tools prepend to the file
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			return nil, errors.New(fmt.Sprintf("missing required '%s' column", name))
+		}
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to read row: %v", err))
+	}
+
+	return &csvTable{columns: columns, rows: rows}, nil
+}
+
+func (t *csvTable) value(row []string, name string) string {
+	i, ok := t.columns[strings.ToLower(name)]
+	if !ok || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
+
+// line returns line number of the row in the file, including the header row
+func (t *csvTable) line(row int) int {
+	return row + 2
+}
+
+// readJsonEntries reads array of entries with 'date', 'start', 'end' and optional 'note', 'projectId' and 'taskId'
+func readJsonEntries(reader io.Reader) ([]Entry, error) {
+	var entries []Entry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to unmarshal json: %v", err))
+	}
+
+	return entries, nil
+}
+
+// importRange returns date range covering all imported entries, where end date is exclusive
+func importRange(entries []Entry) (string, string, error) {
+	if len(entries) == 0 {
+		return "", "", errors.New("import file has no entries")
+	}
+
+	var first, last time.Time
+	for i, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil {
+			return "", "", errors.New(fmt.Sprintf("entry #%d: unable to parse date '%s', expected YYYY-MM-DD", i+1, entry.Date))
+		}
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+	}
+
+	return first.Format("2006-01-02"), last.AddDate(0, 0, 1).Format("2006-01-02"), nil
+}
+
+// validateImportedEntries checks imported entries for unparseable times, overlaps, holidays and labor-law
+// compliance, skips days
+// which already have hours logged and fills in the employee and default project
+func validateImportedEntries(entries []Entry, report Report, allowHolidays bool) ([]Entry, error) {
+	type interval struct {
+		row        int
+		start, end time.Time
+	}
+
+	var problems []string
+	var valid []Entry
+	days := make(map[string][]interval)
+
+	for i, entry := range entries {
+		row := i + 1
+		if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+			problems = append(problems, fmt.Sprintf("entry #%d: unable to parse date '%s', expected YYYY-MM-DD", row, entry.Date))
+			continue
+		}
+		start, end, err := entryInterval(entry)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("entry #%d: %v", row, err))
+			continue
+		}
+		if !end.After(start) {
+			problems = append(problems, fmt.Sprintf("entry #%d: end %s should be after start %s", row, entry.End, entry.Start))
+			continue
+		}
+		if err := validateProject(entry.ProjectId, entry.TaskId); err != nil {
+			problems = append(problems, fmt.Sprintf("entry #%d: %v", row, err))
+			continue
+		}
+		if entry.Date < startDate || entry.Date >= endDate {
+			fmt.Printf("Excluded entry #%d because %s is outside of the selected date range \n", row, entry.Date)
+			continue
+		}
+		if holiday, ok := holidays[entry.Date]; ok && !allowHolidays {
+			problems = append(problems, fmt.Sprintf("entry #%d: %s is a holiday (%s), use 'allowHolidays' to import it anyway", row, entry.Date, holiday))
+			continue
+		}
+		// skip days when hours were already logged, same as for generated entries
+		if _, ok := report.days[entry.Date]; ok {
+			fmt.Printf("Excluded entry #%d because hours were already logged for %s \n", row, entry.Date)
+			continue
+		}
+
+		days[entry.Date] = append(days[entry.Date], interval{row, start, end})
+
+		entry.EmployeeId = employeeId
+		if entry.ProjectId == 0 {
+			entry.ProjectId, entry.TaskId = projectId, taskId
+		}
+		if entry.Note == "" {
+			entry.Note = note
+		}
+		valid = append(valid, entry)
+	}
+
+	dates := slices.Sorted(maps.Keys(days))
+	for _, date := range dates {
+		intervals := days[date]
+		slices.SortFunc(intervals, func(a, b interval) int { return a.start.Compare(b.start) })
+		for i := 1; i < len(intervals); i++ {
+			if intervals[i].start.Before(intervals[i-1].end) {
+				problems = append(problems, fmt.Sprintf("entry #%d: overlaps with entry #%d on %s", intervals[i].row, intervals[i-1].row, date))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		problems = append(problems, checkImportCompliance(valid, report, dates)...)
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+
+	slices.SortStableFunc(valid, func(a, b Entry) int {
+		return strings.Compare(a.Date+a.Start, b.Date+b.Start)
+	})
+
+	return valid, nil
+}
+
+// checkImportCompliance checks imported days against labor-law rules, together with days already logged
+func checkImportCompliance(entries []Entry, report Report, dates []string) []string {
+	var problems []string
+	timeline := reportTimeline(report)
+	for _, date := range dates {
+		day, err := newWorkDay(dayEntries(entries, date))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", date, err))
+			continue
+		}
+		timeline[date] = day
+	}
+
+	weeks := make(map[string]bool)
+	for _, date := range dates {
+		for _, v := range compliance.checkDay(date, timeline) {
+			problems = append(problems, v.String())
+		}
+		// report each week once
+		t, _ := time.Parse("2006-01-02", date)
+		week := startOfWeek(t).Format("2006-01-02")
+		if v, broken := compliance.checkWeek(date, timeline); broken && !weeks[week] {
+			weeks[week] = true
+			problems = append(problems, v.String())
+		}
+	}
+
+	return problems
+}
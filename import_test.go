@@ -0,0 +1,151 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCsvEntries(t *testing.T) {
+	records := `date,start,end,note,projectId
+2025-03-13,08:00,12:00,Code review,5
+2025-03-13, 12:30,16:30,,
+`
+	want := []Entry{
+		{Date: "2025-03-13", Start: "08:00", End: "12:00", Note: "Code review", ProjectId: 5},
+		{Date: "2025-03-13", Start: "12:30", End: "16:30"},
+	}
+
+	got, err := readCsvEntries(strings.NewReader(records))
+	if err != nil {
+		t.Fatalf("readCsvEntries() error = %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("readCsvEntries() = %v, want %v", got, want)
+	}
+
+	_, err = readCsvEntries(strings.NewReader("date,start\n2025-03-13,08:00\n"))
+	if err == nil {
+		t.Errorf("readCsvEntries() should return error when 'end' column is missing")
+	}
+}
+
+func TestReadJsonEntries(t *testing.T) {
+	records := `[{"date":"2025-03-13","start":"08:00","end":"12:00","note":"Code review","projectId":5,"taskId":12}]`
+	want := []Entry{{Date: "2025-03-13", Start: "08:00", End: "12:00", Note: "Code review", ProjectId: 5, TaskId: 12}}
+
+	got, err := readJsonEntries(strings.NewReader(records))
+	if err != nil {
+		t.Fatalf("readJsonEntries() error = %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("readJsonEntries() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateImportedEntries(t *testing.T) {
+	originalStart, originalEnd, originalHolidays, originalCompliance := startDate, endDate, holidays, compliance
+	startDate, endDate = "2025-03-10", "2025-03-15"
+	holidays = map[string]string{"2025-03-12": "test holiday"}
+	compliance, _ = resolveCompliance("SI", ComplianceConfig{})
+	t.Cleanup(func() {
+		startDate, endDate, holidays, compliance = originalStart, originalEnd, originalHolidays, originalCompliance
+	})
+
+	report := Report{days: map[string]DayReport{"2025-03-11": {workHours: 8}}}
+
+	tests := []struct {
+		name          string
+		entries       []Entry
+		allowHolidays bool
+		want          []Entry
+		wantErr       bool
+	}{
+		{
+			"ValidSortedEntries",
+			[]Entry{
+				{Date: "2025-03-13", Start: "12:30", End: "16:30"},
+				{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+			},
+			false,
+			[]Entry{
+				{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+				{Date: "2025-03-13", Start: "12:30", End: "16:30"},
+			},
+			false,
+		},
+		{
+			"SkipLoggedAndOutOfRangeDays",
+			[]Entry{
+				{Date: "2025-03-11", Start: "08:00", End: "12:00"},
+				{Date: "2025-03-17", Start: "08:00", End: "12:00"},
+				{Date: "2025-03-14", Start: "08:00", End: "12:00"},
+			},
+			false,
+			[]Entry{{Date: "2025-03-14", Start: "08:00", End: "12:00"}},
+			false,
+		},
+		{
+			"Holiday",
+			[]Entry{{Date: "2025-03-12", Start: "08:00", End: "12:00"}},
+			false,
+			nil,
+			true,
+		},
+		{
+			"AllowedHoliday",
+			[]Entry{{Date: "2025-03-12", Start: "08:00", End: "12:00"}},
+			true,
+			[]Entry{{Date: "2025-03-12", Start: "08:00", End: "12:00"}},
+			false,
+		},
+		{
+			"Overlap",
+			[]Entry{
+				{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+				{Date: "2025-03-13", Start: "11:30", End: "16:30"},
+			},
+			false,
+			nil,
+			true,
+		},
+		{
+			"MissingBreak",
+			[]Entry{
+				{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+				{Date: "2025-03-13", Start: "12:05", End: "16:00"},
+			},
+			false,
+			nil,
+			true,
+		},
+		{
+			"InvalidTime",
+			[]Entry{{Date: "2025-03-13", Start: "8am", End: "12:00"}},
+			false,
+			nil,
+			true,
+		},
+		{
+			"EndBeforeStart",
+			[]Entry{{Date: "2025-03-13", Start: "12:00", End: "08:00"}},
+			false,
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := validateImportedEntries(test.entries, report, test.allowHolidays)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateImportedEntries() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if !test.wantErr && !reflect.DeepEqual(test.want, got) {
+				t.Errorf("validateImportedEntries() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
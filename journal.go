@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	JournalClock  = "clock"
+	JournalManual = "manual"
+)
+
+// JournalEntry is a clock entry recorded locally, while BambooHR was unreachable or entered manually. Entries
+// started by offline clock in have no end until clock out
+type JournalEntry struct {
+	Entry
+	Source string `json:"source"`
+}
+
+// journalPath returns location of the offline journal in user's config directory
+func journalPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("unable to find config directory: %v", err))
+	}
+
+	return filepath.Join(dir, "bamboo", "journal.json"), nil
+}
+
+// loadJournal returns empty journal when the file doesn't exist yet
+func loadJournal(path string) ([]JournalEntry, error) {
+	var journal []JournalEntry
+	file, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to read journal: %v", err))
+	}
+	if err := json.Unmarshal(file, &journal); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to parse journal '%s': %v", path, err))
+	}
+
+	return journal, nil
+}
+
+func saveJournal(path string, journal []JournalEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.New(fmt.Sprintf("unable to create journal directory: %v", err))
+	}
+	body, _ := json.MarshalIndent(journal, "", "  ")
+	if err := os.WriteFile(path, body, 0o600); err != nil {
+		return errors.New(fmt.Sprintf("unable to write journal: %v", err))
+	}
+
+	return nil
+}
+
+// appendJournal adds the entry to the journal file
+func appendJournal(entry JournalEntry) error {
+	path, err := journalPath()
+	if err != nil {
+		return err
+	}
+	journal, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	return saveJournal(path, append(journal, entry))
+}
+
+// closeJournalEntry sets the end of the last entry started by offline clock in
+func closeJournalEntry(date string, end string) error {
+	path, err := journalPath()
+	if err != nil {
+		return err
+	}
+	journal, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	for i := len(journal) - 1; i >= 0; i-- {
+		if journal[i].Source == JournalClock && journal[i].End == "" && journal[i].Date == date {
+			journal[i].End = end
+			return saveJournal(path, journal)
+		}
+	}
+
+	return errors.New(fmt.Sprintf("no open clock entry on %s in the journal", date))
+}
+
+// processRecord adds manually entered interval eg. 'record 2025-03-14 08:00 12:00' to the journal
+func processRecord(args []string) {
+	if len(args) != 3 {
+		fmt.Println("Invalid interval provided eg. 'record 2025-03-14 08:00 12:00'. Aborting")
+		os.Exit(1)
+	}
+	date, err := parseDate(args[0])
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	entry := Entry{Date: date.Format("2006-01-02"), Start: args[1], End: args[2], ProjectId: projectId, TaskId: taskId, Note: note}
+	if err := validateDay([]Entry{entry}); err != nil {
+		fmt.Printf("Invalid interval: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if err := appendJournal(JournalEntry{Entry: entry, Source: JournalManual}); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Recorded %s %s - %s, use 'sync' to push it to BambooHR \n", entry.Date, entry.Start, entry.End)
+}
+
+// completedEntries returns journal entries which can be synced, entries without end are still running
+func completedEntries(journal []JournalEntry) []Entry {
+	var entries []Entry
+	for _, entry := range journal {
+		if entry.End != "" {
+			entries = append(entries, entry.Entry)
+		}
+	}
+
+	return entries
+}
+
+// SyncPlan splits journal entries by what happens to them on sync
+type SyncPlan struct {
+	push []Entry
+	// already contains entries which BambooHR already has
+	already []Entry
+	// conflicts describe days where BambooHR has different entries than the journal
+	conflicts []string
+}
+
+// planSync pushes days which aren't logged in BambooHR yet. Days which are logged are either already synced,
+// when BambooHR has every journal entry of the day, or in conflict
+func planSync(entries []Entry, workingHours []TimeEntry) SyncPlan {
+	logged := make(map[string][]string)
+	for _, entry := range workingHours {
+		logged[entry.Date] = append(logged[entry.Date], formatLocalTime(entry.Start)+"-"+formatLocalTime(entry.End))
+	}
+
+	var plan SyncPlan
+	dates := make(map[string][]Entry)
+	for _, entry := range entries {
+		dates[entry.Date] = append(dates[entry.Date], entry)
+	}
+	for _, date := range slices.Sorted(maps.Keys(dates)) {
+		day := dates[date]
+		existing, ok := logged[date]
+		if !ok {
+			plan.push = append(plan.push, day...)
+			continue
+		}
+
+		var missing []string
+		for _, entry := range day {
+			if interval := entry.Start + "-" + entry.End; !slices.Contains(existing, interval) {
+				missing = append(missing, interval)
+			}
+		}
+		if len(missing) == 0 {
+			plan.already = append(plan.already, day...)
+			continue
+		}
+		slices.Sort(existing)
+		plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s: journal has %s, BambooHR has %s", date, strings.Join(missing, ", "), strings.Join(existing, ", ")))
+	}
+
+	return plan
+}
+
+// unsyncedEntries returns journal entries, which are neither in BambooHR already nor were stored. Stored entries are
+// matched by their date and times, as employee and project are filled in before they're stored
+func unsyncedEntries(journal []JournalEntry, already []Entry, stored []Entry) []JournalEntry {
+	var remaining []JournalEntry
+	for _, entry := range journal {
+		isStored := slices.ContainsFunc(stored, func(s Entry) bool {
+			return s.Date == entry.Date && s.Start == entry.Start && s.End == entry.End
+		})
+		if entry.End != "" && (isStored || slices.Contains(already, entry.Entry)) {
+			continue
+		}
+		remaining = append(remaining, entry)
+	}
+
+	return remaining
+}
+
+// processSync pushes missing journal entries to BambooHR and removes synced entries from the journal, entries
+// in conflict are kept until they're resolved manually
+func processSync(journal []JournalEntry, workingHours []TimeEntry, report Report, force bool) {
+	plan := planSync(completedEntries(journal), workingHours)
+
+	for _, entry := range journal {
+		if entry.End == "" {
+			fmt.Printf("Skipped entry started on %s at %s, it's still running \n", entry.Date, entry.Start)
+		}
+	}
+	for _, conflict := range plan.conflicts {
+		fmt.Printf("Conflict on %s \n", conflict)
+	}
+	if len(plan.already) > 0 {
+		fmt.Printf("%d entries are already in BambooHR \n", len(plan.already))
+	}
+
+	var stored []Entry
+	if len(plan.push) > 0 {
+		entries, err := validateImportedEntries(plan.push, report, false)
+		if err != nil {
+			fmt.Printf("Invalid journal entries: \n%v \n", err)
+			os.Exit(1)
+		}
+		stored = submitEntries(entries, report, force)
+	}
+
+	// keep running and conflicting entries only, entries changed or dropped in review are kept as well
+	remaining := unsyncedEntries(journal, plan.already, stored)
+
+	path, err := journalPath()
+	if err == nil {
+		err = saveJournal(path, remaining)
+	}
+	if err != nil {
+		fmt.Printf("%v \n", err)
+		os.Exit(1)
+	}
+	if len(plan.push) == 0 && len(plan.conflicts) == 0 {
+		fmt.Println("Journal is in sync with BambooHR")
+	}
+}
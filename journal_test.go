@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// useConfigDir points user's config directory to a temporary directory
+func useConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+func TestJournalOfflineClock(t *testing.T) {
+	useConfigDir(t)
+
+	manual := JournalEntry{Entry: Entry{Date: "2025-03-13", Start: "08:00", End: "12:00"}, Source: JournalManual}
+	if err := appendJournal(manual); err != nil {
+		t.Fatalf("appendJournal() error = %v", err)
+	}
+	if err := appendJournal(JournalEntry{Entry: Entry{Date: "2025-03-14", Start: "08:30", Note: "Offline"}, Source: JournalClock}); err != nil {
+		t.Fatalf("appendJournal() error = %v", err)
+	}
+	if err := closeJournalEntry("2025-03-13", "16:00"); err == nil {
+		t.Errorf("closeJournalEntry() should return error when there is no open entry on the date")
+	}
+	if err := closeJournalEntry("2025-03-14", "12:15"); err != nil {
+		t.Fatalf("closeJournalEntry() error = %v", err)
+	}
+
+	path, _ := journalPath()
+	journal, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	want := []JournalEntry{
+		manual,
+		{Entry: Entry{Date: "2025-03-14", Start: "08:30", End: "12:15", Note: "Offline"}, Source: JournalClock},
+	}
+	if !reflect.DeepEqual(want, journal) {
+		t.Errorf("loadJournal() = %v, want %v", journal, want)
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	loc := useLjubljana(t)
+	at := func(date string, clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+		return t
+	}
+	workingHours := []TimeEntry{
+		{Date: "2025-03-12", Start: at("2025-03-12", "08:00"), End: at("2025-03-12", "12:00")},
+		{Date: "2025-03-12", Start: at("2025-03-12", "12:30"), End: at("2025-03-12", "16:30")},
+		{Date: "2025-03-13", Start: at("2025-03-13", "09:00"), End: at("2025-03-13", "17:00")},
+	}
+	entries := []Entry{
+		{Date: "2025-03-14", Start: "08:00", End: "12:00"},
+		{Date: "2025-03-12", Start: "12:30", End: "16:30"},
+		{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+		{Date: "2025-03-14", Start: "12:30", End: "16:00"},
+	}
+
+	got := planSync(entries, workingHours)
+
+	want := SyncPlan{
+		push:      []Entry{entries[0], entries[3]},
+		already:   []Entry{entries[1]},
+		conflicts: []string{"2025-03-13: journal has 08:00-12:00, BambooHR has 09:00-17:00"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("planSync() = %v, want %v", got, want)
+	}
+}
+
+func TestUnsyncedEntries(t *testing.T) {
+	already := JournalEntry{Entry: Entry{Date: "2025-03-10", Start: "08:00", End: "16:00"}, Source: JournalManual}
+	pushed := JournalEntry{Entry: Entry{Date: "2025-03-11", Start: "08:00", End: "16:00"}, Source: JournalClock}
+	edited := JournalEntry{Entry: Entry{Date: "2025-03-12", Start: "08:00", End: "16:00"}, Source: JournalClock}
+	dropped := JournalEntry{Entry: Entry{Date: "2025-03-13", Start: "08:00", End: "16:00"}, Source: JournalManual}
+	running := JournalEntry{Entry: Entry{Date: "2025-03-14", Start: "08:00"}, Source: JournalClock}
+	journal := []JournalEntry{already, pushed, edited, dropped, running}
+	// employee and project are filled in, and the edited entry was shifted in review
+	stored := []Entry{
+		{EmployeeId: 12, Date: "2025-03-11", Start: "08:00", End: "16:00", ProjectId: 5},
+		{EmployeeId: 12, Date: "2025-03-12", Start: "09:00", End: "17:00", ProjectId: 5},
+	}
+
+	got := unsyncedEntries(journal, []Entry{already.Entry}, stored)
+
+	want := []JournalEntry{edited, dropped, running}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unsyncedEntries() = %v, want %v", got, want)
+	}
+}
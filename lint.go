@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// gaps between entries of at least this length count as a break, logged entries are always work
+const breakLength = 30 * time.Minute
+
+const (
+	IssueOverlap      = "overlap"
+	IssueLongDay      = "long day"
+	IssueMissingBreak = "missing break"
+	IssueHoliday      = "public holiday"
+	IssueTimeOff      = "time off"
+	IssueOutsideHours = "outside normal hours"
+)
+
+// LintOptions holds thresholds of the timesheet checks
+type LintOptions struct {
+	maxDay time.Duration
+	// continuous work longer than this needs a break
+	breakAfter time.Duration
+	// normal working hours in employee's time zone, in 15:04 format
+	earliest string
+	latest   string
+}
+
+// LintIssue is a single problem found in the day's entries
+type LintIssue struct {
+	Date   string
+	Kind   string
+	Detail string
+}
+
+// processLint prints issues found in the entries and exits with non-zero code if there are any
+func processLint(entries []TimeEntry, options LintOptions) {
+	issues := lintEntries(entries, options)
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	// table header
+	fmt.Fprintf(w, "Date\tIssue\tDetail\t\n")
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", issue.Date, issue.Kind, issue.Detail)
+	}
+	fmt.Fprintf(w, "\nFound %d issues. Times are shown in %s time zone \n", len(issues), location)
+	w.Flush()
+
+	os.Exit(1)
+}
+
+// lintEntries checks entries for overlaps, long days, missing breaks, entries on days off and entries outside
+// normal working hours
+func lintEntries(entries []TimeEntry, options LintOptions) []LintIssue {
+	var issues []LintIssue
+	sorted := sortTimeEntries(entries)
+
+	for start := 0; start < len(sorted); {
+		date := sorted[start].Date
+		end := start
+		for end < len(sorted) && sorted[end].Date == date {
+			end++
+		}
+		issues = append(issues, lintDay(date, sorted[start:end], options)...)
+		start = end
+	}
+
+	return issues
+}
+
+// lintDay checks entries of a single day, sorted by start time
+func lintDay(date string, entries []TimeEntry, options LintOptions) []LintIssue {
+	var issues []LintIssue
+	addIssue := func(kind string, detail string) {
+		issues = append(issues, LintIssue{Date: date, Kind: kind, Detail: detail})
+	}
+
+	if holiday, ok := publicHolidays[date]; ok {
+		addIssue(IssueHoliday, holiday)
+	}
+	if _, ok := timeOff[date]; ok {
+		addIssue(IssueTimeOff, "entries logged on time off day")
+	}
+
+	total := 0.0
+	var timed []TimeEntry
+	for _, entry := range entries {
+		total += entry.Hours
+		if !entry.Start.IsZero() && !entry.End.IsZero() {
+			timed = append(timed, entry)
+		}
+	}
+	if hours := time.Duration(total * float64(time.Hour)); options.maxDay > 0 && hours > options.maxDay {
+		addIssue(IssueLongDay, fmt.Sprintf("%s logged, max is %s", convertDecimalTimeToTime(total), options.maxDay))
+	}
+
+	var latestEnd time.Time
+	for i, entry := range timed {
+		if i > 0 && entry.Start.Before(latestEnd) {
+			addIssue(IssueOverlap, fmt.Sprintf("%s-%s overlaps earlier entry ending at %s", formatLocalTime(entry.Start), formatLocalTime(entry.End), formatLocalTime(latestEnd)))
+		}
+		latestEnd = maxTime(latestEnd, entry.End)
+
+		start, end := entry.Start.In(location), entry.End.In(location)
+		if start.Format("15:04") < options.earliest || end.Format("15:04") > options.latest || end.Format("2006-01-02") != date {
+			addIssue(IssueOutsideHours, fmt.Sprintf("%s-%s is outside %s-%s", formatLocalTime(entry.Start), formatLocalTime(entry.End), options.earliest, options.latest))
+		}
+	}
+
+	for _, run := range continuousRuns(timed) {
+		if options.breakAfter > 0 && run.end.Sub(run.start) > options.breakAfter {
+			addIssue(IssueMissingBreak, fmt.Sprintf("%s-%s without a break", formatLocalTime(run.start), formatLocalTime(run.end)))
+		}
+	}
+
+	return issues
+}
+
+// continuousRuns returns intervals of continuous work, split by gaps of at least breakLength
+func continuousRuns(entries []TimeEntry) []WorkBlock {
+	var runs []WorkBlock
+	var run *WorkBlock
+
+	for _, entry := range entries {
+		if run == nil || entry.Start.Sub(run.end) >= breakLength {
+			runs = append(runs, WorkBlock{start: entry.Start, end: entry.End})
+			run = &runs[len(runs)-1]
+			continue
+		}
+		run.end = maxTime(run.end, entry.End)
+	}
+
+	return runs
+}
+
+// parseNormalHours parses working hours range eg. 06:00-20:00
+func parseNormalHours(value string) (string, string, error) {
+	earliest, latest, ok := strings.Cut(value, "-")
+	if !ok {
+		return "", "", errors.New(fmt.Sprintf("unable to parse '%s', expected HH:MM-HH:MM", value))
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(earliest))
+	if err != nil {
+		return "", "", errors.New(fmt.Sprintf("unable to parse '%s', expected HH:MM-HH:MM", value))
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(latest))
+	if err != nil {
+		return "", "", errors.New(fmt.Sprintf("unable to parse '%s', expected HH:MM-HH:MM", value))
+	}
+	if !end.After(start) {
+		return "", "", errors.New(fmt.Sprintf("end of '%s' should be after start", value))
+	}
+
+	return start.Format("15:04"), end.Format("15:04"), nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLintEntries(t *testing.T) {
+	loc := useLjubljana(t)
+	originalHolidays, originalTimeOff := publicHolidays, timeOff
+	publicHolidays = map[string]string{"2024-12-25": "Božič"}
+	timeOff = map[string]string{"2024-12-27": timeOffDay}
+	t.Cleanup(func() { publicHolidays, timeOff = originalHolidays, originalTimeOff })
+
+	entry := func(date string, start string, end string) TimeEntry {
+		s, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+start, loc)
+		e, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+end, loc)
+		return TimeEntry{Date: date, Start: s, End: e, Hours: e.Sub(s).Hours()}
+	}
+	options := LintOptions{maxDay: 10 * time.Hour, breakAfter: 6 * time.Hour, earliest: "06:00", latest: "20:00"}
+
+	tests := []struct {
+		name    string
+		entries []TimeEntry
+		want    []string
+	}{
+		{
+			"GeneratedDay",
+			[]TimeEntry{entry("2024-12-23", "08:00", "12:00"), entry("2024-12-23", "12:30", "16:30")},
+			nil,
+		},
+		{
+			"ShortEntryIsWork",
+			[]TimeEntry{entry("2024-12-23", "07:00", "12:00"), entry("2024-12-23", "12:00", "12:20"), entry("2024-12-23", "12:20", "17:00")},
+			[]string{IssueMissingBreak},
+		},
+		{
+			"BreakGap",
+			[]TimeEntry{entry("2024-12-23", "07:00", "12:00"), entry("2024-12-23", "12:30", "17:00")},
+			nil,
+		},
+		{
+			"MissingBreak",
+			[]TimeEntry{entry("2024-12-23", "07:00", "12:00"), entry("2024-12-23", "12:10", "15:00")},
+			[]string{IssueMissingBreak},
+		},
+		{
+			"Overlap",
+			[]TimeEntry{entry("2024-12-23", "08:00", "12:00"), entry("2024-12-23", "11:00", "12:30")},
+			[]string{IssueOverlap},
+		},
+		{
+			"LongDayOutsideHours",
+			[]TimeEntry{entry("2024-12-23", "05:00", "10:00"), entry("2024-12-23", "11:00", "16:00"), entry("2024-12-23", "17:00", "20:30")},
+			[]string{IssueLongDay, IssueOutsideHours, IssueOutsideHours},
+		},
+		{
+			"DaysOff",
+			[]TimeEntry{entry("2024-12-25", "08:00", "12:00"), {Date: "2024-12-27", Hours: 8}},
+			[]string{IssueHoliday, IssueTimeOff},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range lintEntries(test.entries, options) {
+				got = append(got, issue.Kind)
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("lintEntries() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintGeneratedEntries(t *testing.T) {
+	useLjubljana(t)
+	generated, err := generateWorkEntries(Report{days: map[string]DayReport{}}, "2025-03-10", "2025-03-22")
+	if err != nil {
+		t.Fatalf("generateWorkEntries() error = %v", err)
+	}
+
+	var entries []TimeEntry
+	for _, entry := range generated {
+		start, end, _ := entryInterval(entry)
+		entries = append(entries, TimeEntry{Date: entry.Date, Start: start, End: end, Hours: end.Sub(start).Hours()})
+	}
+	options := LintOptions{maxDay: 10 * time.Hour, breakAfter: 6 * time.Hour, earliest: "06:00", latest: "20:00"}
+
+	// lint has to agree with the generator, so generated days have no issues
+	if issues := lintEntries(entries, options); len(issues) > 0 {
+		t.Errorf("lintEntries() of generated entries = %v, want no issues", issues)
+	}
+}
+
+func TestParseNormalHours(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantEarliest string
+		wantLatest   string
+		wantErr      bool
+	}{
+		{"06:00-20:00", "06:00", "20:00", false},
+		{"7:30 - 18:00", "07:30", "18:00", false},
+		{"20:00-06:00", "", "", true},
+		{"06:00", "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			earliest, latest, err := parseNormalHours(test.value)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("parseNormalHours() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if earliest != test.wantEarliest || latest != test.wantLatest {
+				t.Errorf("parseNormalHours() = %s, %s, want %s, %s", earliest, latest, test.wantEarliest, test.wantLatest)
+			}
+		})
+	}
+}
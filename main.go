@@ -1,32 +1,107 @@
 package main
 
 import (
-	"flag"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
+	// embed time zone database, so time zones resolve on systems without one eg. Windows
+	_ "time/tzdata"
 )
 
 var (
-	apiKey       string
-	startDate    string
-	endDate      string
-	year         int
-	excludeDays  string
-	employeeId   int
-	holidays     map[string]string
-	excludedDays map[string]bool
-	force        bool
+	apiKey         string
+	startDate      string
+	endDate        string
+	year           int
+	month          string
+	week           string
+	inclusive      bool
+	groupBy        string
+	excludeDays    string
+	employeeId     int
+	employeeIds    string
+	roster         string
+	holidays       map[string]string
+	publicHolidays map[string]string
+	timeOff        map[string]string
+	excludedDays   map[string]bool
+	force          bool
+	timezone       string
+	projectId      int
+	taskId         int
+	note           string
+	schedule       Schedule
+	// fileConfig is the loaded config file, help shows its values as defaults of flags
+	fileConfig    *Config
+	allowHolidays bool
+	importFormat  string
+	roundMinutes  int
+	generator     string
+	gitRepos      string
+	gitAuthor     string
+	gitPadding    time.Duration
+	icsFile       string
+	icsNotes      bool
+	detailed      bool
+	unapproved    bool
+	entryType     string
+	maxDay        time.Duration
+	breakAfter    time.Duration
+	normalHours   string
+	clockAt       string
+	country       string
+	compliance    ComplianceRules
+	checkDays     int
+	webhook       string
+	addr          string
+	offline       bool
+	cacheTtl      time.Duration
+	baseUrl       string
+	location      = time.Local
 )
 
 const (
-	ActionList     = "list"
-	ActionAdd      = "add"
-	ActionRequired = "required"
+	ActionList       = "list"
+	ActionAdd        = "add"
+	ActionRequired   = "required"
+	ActionProjects   = "projects"
+	ActionImport     = "import"
+	ActionExport     = "export"
+	ActionWhy        = "why"
+	ActionLint       = "lint"
+	ActionIn         = "in"
+	ActionOut        = "out"
+	ActionBreak      = "break"
+	ActionNow        = "now"
+	ActionRecord     = "record"
+	ActionSync       = "sync"
+	ActionBalance    = "balance"
+	ActionCheck      = "check"
+	ActionServe      = "serve"
+	ActionHelp       = "help"
+	ActionCompletion = "completion"
 )
 
-var actions = []string{ActionAdd, ActionList, ActionRequired}
+// ActionFakeServer is hidden from the list of commands, it's meant for local testing only
+const ActionFakeServer = "fake-server"
+
+// ActionComplete is hidden from the list of commands, completion scripts call it for values of flags
+const ActionComplete = "__complete"
+
+const (
+	GeneratorTemplate = "template"
+	GeneratorGit      = "git"
+	GeneratorIcs      = "ics"
+)
+
+// defaultBaseUrl is the company's BambooHR API
+const defaultBaseUrl = "https://api.bamboohr.com/api/gateway.php/flaviar/v1"
+
+var generators = []string{GeneratorTemplate, GeneratorGit, GeneratorIcs}
 
 func main() {
 	config, err := loadConfig("config.json")
@@ -34,89 +109,469 @@ func main() {
 		fmt.Printf("Unable to load config file - %v. Aborting", err)
 		os.Exit(1)
 	}
+	if config.Schedule.Git.Padding != "" {
+		if _, err := time.ParseDuration(config.Schedule.Git.Padding); err != nil {
+			fmt.Printf("Invalid git padding in config file: %v. Aborting \n", err)
+			os.Exit(1)
+		}
+	}
 
-	flag.StringVar(&apiKey, "apiKey", config.ApiToken, "Your BambooHR API key")
-	flag.IntVar(&employeeId, "employeeId", config.EmployeeId, "Your BambooHR employee ID")
-	flag.StringVar(&startDate, "start", "", "Start date filter for tracked working hours")
-	flag.StringVar(&endDate, "end", "", "End date filter for tracked working hours")
-	flag.IntVar(&year, "year", 0, "Year for fetching required hours")
-	flag.StringVar(&excludeDays, "excludeDays", "", "Comma-separated list of days (YYYY-MM-DD,YYYY-MM-DD) eg PTO, Collective Leave etc.")
-	flag.BoolVar(&force, "force", false, "Populate work hours without confirmation")
+	runCommand(os.Args[1:], config)
+}
 
-	flag.Parse()
-	action := flag.Arg(0)
-	var workingHours []TimeEntry
+// configure validates settings shared by all commands once flags are parsed
+func configure(config *Config) {
+	var err error
+	fileConfig = config
+	schedule = config.Schedule
+	compliance, err = resolveCompliance(country, config.Compliance)
+	if err != nil {
+		fmt.Printf("Invalid compliance rules: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if err := validateBaseUrl(baseUrl); err != nil {
+		fmt.Printf("Invalid 'baseUrl' provided: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+}
 
-	if action == ActionRequired {
-		if year == 0 {
-			fmt.Println("Invalid 'year' provided. Aborting")
-			os.Exit(1)
-		}
-	} else {
-		if apiKey == "" {
-			fmt.Println("Invalid 'apiKey' provided. Aborting")
-			os.Exit(1)
-		}
-		if employeeId == 0 {
-			fmt.Println("Invalid 'employeeId' provided. Aborting")
-			os.Exit(1)
-		}
-		if startDate == "" {
-			fmt.Println("Invalid 'start' date filter provided. Aborting")
-			os.Exit(1)
-		}
-		if endDate == "" {
-			fmt.Println("Invalid 'end' date filter provided. Aborting")
-			os.Exit(1)
-		}
-		start, err := time.Parse("2006-01-02", startDate)
-		if err != nil {
-			fmt.Println("Unable to parse 'start' date. Aborting")
-			os.Exit(1)
-		}
-		end, err := time.Parse("2006-01-02", endDate)
+func runList(args []string) {
+	expectArgs(ActionList, args, 0)
+	if isTeamMode() {
+		runTeam(ActionList)
+		return
+	}
+	workingHours, report := loadReport()
+	if detailed {
+		processDetailedList(filterEntries(workingHours, unapproved, entryType))
+		return
+	}
+	processList(report, startDate, endDate)
+}
+
+func runBalance(args []string) {
+	expectArgs(ActionBalance, args, 0)
+	if isTeamMode() {
+		runTeam(ActionBalance)
+		return
+	}
+	_, report := loadReport()
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+	processBalance([]TeamBalance{calculateBalance(TeamMember{Id: employeeId}, report, timeOff, start, end)})
+}
+
+func runAdd(args []string) {
+	expectArgs(ActionAdd, args, 0)
+	_, report := loadReport()
+	var err error
+	blockSource, err = newBlockSource(generator)
+	if err != nil {
+		fmt.Printf("Unable to prepare '%s' generator: %v. Aborting \n", generator, err)
+		os.Exit(1)
+	}
+	addWorkingHours(report, force)
+}
+
+func runImport(args []string) {
+	expectArgs(ActionImport, args, 1)
+	if !slices.Contains(importFormats, importFormat) {
+		fmt.Printf("Invalid 'format' provided, use one of: %s. Aborting \n", strings.Join(importFormats, ", "))
+		os.Exit(1)
+	}
+	if roundMinutes < 0 || roundMinutes > 60 {
+		fmt.Println("Invalid 'round' provided, use minutes between 0 and 60. Aborting")
+		os.Exit(1)
+	}
+	imported, err := readImportFile(firstArg(args), importFormat, roundMinutes)
+	if err != nil {
+		fmt.Printf("Unable to read import file: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	// default to the range covered by the file
+	if startDate == "" && endDate == "" && month == "" && week == "" {
+		startDate, endDate, err = importRange(imported)
 		if err != nil {
-			fmt.Println("Unable to parse 'end' date. Aborting")
+			fmt.Printf("Invalid import file: %v. Aborting \n", err)
 			os.Exit(1)
 		}
-		if start.After(end) {
-			fmt.Println("'end' date cannot be before 'start' date")
+	}
+	_, report := loadReport()
+	importEntries(report, imported, allowHolidays, force)
+}
+
+func runExport(args []string) {
+	expectArgs(ActionExport, args, 1)
+	workingHours, _ := loadReport()
+	exportEntries(workingHours, firstArg(args))
+}
+
+func runWhy(args []string) {
+	expectArgs(ActionWhy, args, 1)
+	date, err := parseDate(firstArg(args))
+	if err != nil {
+		fmt.Printf("Invalid date provided eg. 'why 2025-03-14': %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	startDate = date.Format("2006-01-02")
+	endDate = date.AddDate(0, 0, 1).Format("2006-01-02")
+	_, report := loadReport()
+	processWhy(evaluateDay(date, report))
+}
+
+func runLint(args []string) {
+	expectArgs(ActionLint, args, 0)
+	earliest, latest, err := parseNormalHours(normalHours)
+	if err != nil {
+		fmt.Printf("Invalid 'normalHours' provided: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	workingHours, _ := loadReport()
+	processLint(workingHours, LintOptions{maxDay: maxDay, breakAfter: breakAfter, earliest: earliest, latest: latest})
+}
+
+// runCheck checks the last workdays before today, today's hours usually aren't logged yet
+func runCheck(args []string) {
+	expectArgs(ActionCheck, args, 0)
+	if checkDays <= 0 {
+		fmt.Println("Invalid 'days' provided, use a positive number of workdays. Aborting")
+		os.Exit(1)
+	}
+	// fetch a wider window first, so workdays are counted with the same day rules as the rest of the app
+	end := today()
+	earliest := end.AddDate(0, 0, -checkWindow(checkDays))
+	startDate = earliest.Format("2006-01-02")
+	endDate = end.Format("2006-01-02")
+	_, report := loadReport()
+	start, end := checkRange(end, checkDays, report, earliest)
+	processCheck(report, start, end, webhook)
+}
+
+func runClock(action string) func(args []string) {
+	return func(args []string) {
+		expectArgs(action, args, 0)
+		validateCredentials()
+		if err := validateProject(projectId, taskId); err != nil {
+			fmt.Printf("%v. Aborting \n", err)
 			os.Exit(1)
 		}
-
-		workingHours, err = fetchWorkingHours()
+		var err error
+		location, err = resolveLocation(timezone, nil)
 		if err != nil {
-			fmt.Printf("Failed fetching working hours: %v \n", err)
+			fmt.Printf("Invalid 'timezone' provided: %v. Aborting \n", err)
 			os.Exit(1)
 		}
+		processClock(action, clockAt)
+	}
+}
+
+// runNow fetches days around today, because employee's today depends on the time zone resolved from the entries
+func runNow(args []string) {
+	expectArgs(ActionNow, args, 0)
+	startDate = today().AddDate(0, 0, -1).Format("2006-01-02")
+	endDate = today().AddDate(0, 0, 2).Format("2006-01-02")
+	workingHours, report := loadReport()
+	processNow(workingHours, report)
+}
+
+func runRecord(args []string) {
+	if err := validateProject(projectId, taskId); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	var err error
+	location, err = resolveLocation(timezone, nil)
+	if err != nil {
+		fmt.Printf("Invalid 'timezone' provided: %v. Aborting \n", err)
+		os.Exit(1)
 	}
+	processRecord(args)
+}
 
+// runSync syncs the range covered by the journal
+func runSync(args []string) {
+	expectArgs(ActionSync, args, 0)
+	path, err := journalPath()
+	var journal []JournalEntry
+	if err == nil {
+		journal, err = loadJournal(path)
+	}
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	completed := completedEntries(journal)
+	if len(completed) == 0 {
+		fmt.Println("There are no journal entries to sync. Exiting the program...")
+		return
+	}
+	startDate, endDate, err = importRange(completed)
+	if err != nil {
+		fmt.Printf("Invalid journal: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	workingHours, report := loadReport()
+	processSync(journal, workingHours, report, force)
+}
+
+func runProjects(args []string) {
+	expectArgs(ActionProjects, args, 0)
+	validateCredentials()
+	projects, err := fetchProjects()
+	if err != nil {
+		fmt.Printf("Failed fetching projects: %v \n", err)
+		os.Exit(1)
+	}
+	processProjects(projects)
+}
+
+func runRequired(args []string) {
+	expectArgs(ActionRequired, args, 0)
+	start, end, err := resolveRequiredRange(year, startDate, endDate, month, week, inclusive)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if !slices.Contains(groupings, groupBy) {
+		fmt.Printf("Invalid 'groupBy' provided, use one of: %s. Aborting \n", strings.Join(groupings, ", "))
+		os.Exit(1)
+	}
+	calendar, err := requiredHolidays(start, end)
+	if err != nil {
+		fmt.Printf("Cannot load holidays: %v . Aborting \n", err)
+		os.Exit(1)
+	}
+	processRequiredHours(start, end, groupBy, calendar)
+}
+
+func runServe(args []string) {
+	expectArgs(ActionServe, args, 0)
+	validateCredentials()
+	if err := validateProject(projectId, taskId); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if err := validateSchedule(schedule); err != nil {
+		fmt.Printf("Invalid schedule in config file: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	var err error
+	excludedDays, err = loadExcludedDays(excludeDays)
+	if err != nil {
+		fmt.Printf("Cannot parse excluded days: %v", err)
+		os.Exit(1)
+	}
+	processServe(addr)
+}
+
+func runHelp(args []string) {
+	expectArgs(ActionHelp, args, 1)
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	command := findCommand(args[0])
+	if command == nil {
+		fmt.Printf("Unknown command '%s'\n\n", args[0])
+		printUsage(os.Stdout)
+		os.Exit(1)
+	}
+	printHelp(os.Stdout, command, fileConfig)
+}
+
+func runCompletion(args []string) {
+	expectArgs(ActionCompletion, args, 1)
+	if err := writeCompletion(os.Stdout, firstArg(args)); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+}
+
+// runComplete prints values of the flag one per line, failures print nothing so they don't end up in the shell
+func runComplete(args []string) {
+	for _, value := range completionValues(firstArg(args)) {
+		fmt.Println(value)
+	}
+}
+
+func isTeamMode() bool {
+	return employeeIds != "" || roster != ""
+}
+
+// runTeam reports on multiple employees, so it skips employee's own time off and working hours
+func runTeam(action string) {
+	if apiKey == "" {
+		fmt.Println("Invalid 'apiKey' provided. Aborting")
+		os.Exit(1)
+	}
+	members, err := loadTeam(employeeIds, roster)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if len(members) == 0 {
+		fmt.Println("Roster has no employees. Aborting")
+		os.Exit(1)
+	}
+	start, end, err := resolveDateRange(startDate, endDate, month, week, inclusive)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	startDate = start.Format("2006-01-02")
+	endDate = end.Format("2006-01-02")
+
+	entries, err := fetchTeamHours(members)
+	if err != nil {
+		fmt.Printf("Failed fetching working hours: %v \n", err)
+		os.Exit(1)
+	}
+	location, err = resolveLocation(timezone, entries)
+	if err != nil {
+		fmt.Printf("Invalid 'timezone' provided: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	holidayFetcher := NewCsvHolidays("slovenian_public_work_off_days.csv")
+	publicHolidays, err = holidayFetcher.loadPublicHolidays()
+	if err != nil {
+		fmt.Printf("Cannot load holidays: %v . Aborting \n", err)
+		os.Exit(1)
+	}
+	timeOffs, companyHolidays, err := holidayFetcher.fetchTeamTimeOff()
+	if err != nil {
+		fmt.Printf("Cannot load time off: %v . Aborting \n", err)
+		os.Exit(1)
+	}
+	publicHolidays = combineDaysOff(publicHolidays, companyHolidays)
+
+	reports := groupHoursByEmployee(members, entries)
+	if action == ActionList {
+		processTeamList(members, reports, timeOffs, start, end)
+		return
+	}
+	processBalance(teamBalances(members, reports, timeOffs, start, end))
+}
+
+// loadReport fetches employee's working hours of the date range, and loads days off of the range
+func loadReport() ([]TimeEntry, Report) {
+	validateCredentials()
+	if err := validateProject(projectId, taskId); err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	if err := validateSchedule(schedule); err != nil {
+		fmt.Printf("Invalid schedule in config file: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	start, end, err := resolveDateRange(startDate, endDate, month, week, inclusive)
+	if err != nil {
+		fmt.Printf("%v. Aborting \n", err)
+		os.Exit(1)
+	}
+	// normalize shorthands, so the rest of the app works with YYYY-MM-DD dates and exclusive end date
+	startDate = start.Format("2006-01-02")
+	endDate = end.Format("2006-01-02")
+
+	workingHours, err := fetchWorkingHours()
+	if err != nil {
+		fmt.Printf("Failed fetching working hours: %v \n", err)
+		os.Exit(1)
+	}
+	location, err = resolveLocation(timezone, workingHours)
+	if err != nil {
+		fmt.Printf("Invalid 'timezone' provided: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+	loadDays()
+
+	return workingHours, groupHoursByDate(workingHours)
+}
+
+// loadDays loads public holidays, time off and excluded days
+func loadDays() {
+	var err error
 	holidayFetcher := NewCsvHolidays("slovenian_public_work_off_days.csv")
-	holidays, err = holidayFetcher.loadHolidays()
+	publicHolidays, timeOff, err = holidayFetcher.loadDaysOff()
 	if err != nil {
 		fmt.Printf("Cannot load holidays: %v . Aborting \n", err)
 		os.Exit(1)
 	}
+	// public holidays and time off are kept apart as well, so day rules can tell their sources apart
+	holidays = combineDaysOff(publicHolidays, timeOff)
 	excludedDays, err = loadExcludedDays(excludeDays)
 	if err != nil {
 		fmt.Printf("Cannot parse excluded days: %v", err)
 		os.Exit(1)
 	}
+}
+
+// newBlockSource returns source of work blocks for the generator, or nil for the randomized template
+func newBlockSource(generator string) (WorkBlockSource, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, err
+	}
 
-	report := groupHoursByDate(workingHours)
+	switch generator {
+	case GeneratorTemplate:
+		return nil, nil
+	case GeneratorGit:
+		var repos []string
+		if gitRepos != "" {
+			repos = strings.Split(gitRepos, ",")
+		}
+		source, err := NewGitBlockSource(repos, gitAuthor, start, end, gitPadding)
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	case GeneratorIcs:
+		if icsFile == "" {
+			return nil, errors.New("missing 'icsFile'")
+		}
+		source, err := NewIcsBlockSource(icsFile, start, end, icsNotes)
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	}
 
-	switch action {
-	case ActionList:
-		processList(report)
-		os.Exit(0)
-	case ActionAdd:
-		addWorkingHours(report, force)
-		os.Exit(0)
-	case ActionRequired:
-		processRequiredHours()
-		os.Exit(0)
-	default:
-		fmt.Printf("No argument provided. You need to choose one of the supported actions: %s \n", strings.Join(actions, ", "))
+	return nil, errors.New(fmt.Sprintf("unsupported generator, use one of: %s", strings.Join(generators, ", ")))
+}
+
+// apiUrl returns URL of the BambooHR API endpoint eg. '/time_off/whos_out', authenticated with the API key
+func apiUrl(path string, args ...any) string {
+	// base URL is validated on start
+	u, _ := url.Parse(baseUrl)
+	u.User = url.UserPassword(apiKey, "x")
+
+	return strings.TrimSuffix(u.String(), "/") + fmt.Sprintf(path, args...)
+}
+
+func validateBaseUrl(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return errors.New(fmt.Sprintf("'%s' should be absolute http or https URL", value))
+	}
+
+	return nil
+}
+
+// validateCredentials aborts the program when BambooHR credentials are missing
+func validateCredentials() {
+	if apiKey == "" {
+		fmt.Println("Invalid 'apiKey' provided. Aborting")
+		os.Exit(1)
+	}
+	if employeeId == 0 {
+		fmt.Println("Invalid 'employeeId' provided. Aborting")
 		os.Exit(1)
 	}
 }
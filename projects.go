@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+type Project struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Billable bool   `json:"billable"`
+	HasTasks bool   `json:"hasTasks"`
+	Tasks    []Task `json:"tasks"`
+}
+
+type Task struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Billable bool   `json:"billable"`
+}
+
+// ProjectInfo is project and task assigned to the tracked time entry
+type ProjectInfo struct {
+	Project Project `json:"project"`
+	Task    *Task   `json:"task"`
+}
+
+func processProjects(projects []Project) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	defer w.Flush()
+	// table header
+	fmt.Fprintf(w, "Project ID\tTask ID\tName\tBillable\t\n")
+
+	for _, project := range projects {
+		fmt.Fprintf(w, "%d\t-\t%s\t%t\n", project.Id, project.Name, project.Billable)
+		for _, task := range project.Tasks {
+			fmt.Fprintf(w, "%d\t%d\t  %s\t%t\n", project.Id, task.Id, task.Name, task.Billable)
+		}
+	}
+
+	if len(projects) == 0 {
+		fmt.Fprintf(w, "\nThere are no projects available for employee %d \n", employeeId)
+	}
+}
+
+func fetchProjects() ([]Project, error) {
+	url := apiUrl("/time_tracking/employees/%d/projects", employeeId)
+
+	body, err := cachedFetch(cacheKey("projects", strconv.Itoa(employeeId)), func() ([]byte, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to get projects from Bamboo: %v \n", err))
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to read response body: %v", err))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, errors.New("invalid 'apiToken' provided - API returned 401 (Unauthorized). Aborting")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		}
+
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	err = json.Unmarshal(body, &projects)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to unmarshal json to struct: %v", err))
+	}
+
+	return projects, nil
+}
+
+// validateProject checks that task is only set together with the project
+func validateProject(projectId int, taskId int) error {
+	if projectId < 0 || taskId < 0 {
+		return errors.New("'projectId' and 'taskId' cannot be negative")
+	}
+	if taskId > 0 && projectId == 0 {
+		return errors.New("'taskId' requires 'projectId' to be set")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		projectId int
+		taskId    int
+		wantErr   bool
+	}{
+		{"NoProject", 0, 0, false},
+		{"ProjectOnly", 5, 0, false},
+		{"ProjectAndTask", 5, 12, false},
+		{"TaskWithoutProject", 0, 12, true},
+		{"NegativeProject", -1, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateProject(test.projectId, test.taskId)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateProject(%d, %d) error = %v, wantErr %v", test.projectId, test.taskId, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTimeEntryProjectInfo(t *testing.T) {
+	body := []byte(`[{"id":1,"employeeId":123,"type":"clock","date":"2025-03-14","note":"Sprint planning","projectInfo":{"project":{"id":5,"name":"Website"},"task":{"id":12,"name":"Development"}}},{"id":2,"note":null,"projectInfo":null}]`)
+
+	var entries TimeEntries
+	if err := json.Unmarshal(body, &entries); err != nil {
+		t.Fatalf("unable to unmarshal time entries: %v", err)
+	}
+
+	if entries[0].Note != "Sprint planning" {
+		t.Errorf("Note = %q, want %q", entries[0].Note, "Sprint planning")
+	}
+	if entries[0].ProjectInfo == nil || entries[0].ProjectInfo.Project.Id != 5 || entries[0].ProjectInfo.Task.Id != 12 {
+		t.Errorf("ProjectInfo = %+v, want project 5 and task 12", entries[0].ProjectInfo)
+	}
+	if entries[1].Note != "" || entries[1].ProjectInfo != nil {
+		t.Errorf("entry without project should have empty note and project info, got %+v", entries[1])
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const reviewHelp = `
+Review commands:
+  drop <date>                    remove all entries of the day
+  shift <date> <HH:MM|+-30m>     move the day to a new start time or by a duration
+  length <date> <block> <3h45m>  change length of the day's block, later blocks are moved accordingly
+  regen <date>                   regenerate the day
+  done                           finish reviewing
+`
+
+// reviewEntries lets user adjust generated entries day by day before they're submitted. Changed days are checked
+// against compliance rules with the rest of the entries and days logged in the report
+func reviewEntries(r *bufio.Reader, entries []Entry, report Report) ([]Entry, error) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	fmt.Print(reviewHelp)
+
+	for {
+		fmt.Printf("\n%s\nreview> ", formatReviewEntries(entries))
+
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to read string from user: %v", err))
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "done" {
+			return entries, nil
+		}
+		if fields[0] == "help" {
+			fmt.Print(reviewHelp)
+			continue
+		}
+
+		updated, err := applyReviewCommand(entries, fields, rng, report)
+		if err != nil {
+			fmt.Printf("Unable to apply '%s': %v \n", strings.TrimSpace(line), err)
+			continue
+		}
+		entries = updated
+	}
+}
+
+// applyReviewCommand applies single review command to entries and returns updated entries
+func applyReviewCommand(entries []Entry, fields []string, rng *rand.Rand, report Report) ([]Entry, error) {
+	if len(fields) < 2 {
+		return nil, errors.New("missing date, type 'help' for the list of commands")
+	}
+	date := fields[1]
+	day := dayEntries(entries, date)
+	if len(day) == 0 {
+		return nil, errors.New(fmt.Sprintf("there are no entries for '%s'", date))
+	}
+
+	switch fields[0] {
+	case "drop":
+		return replaceDayEntries(entries, date, nil), nil
+	case "shift":
+		if len(fields) != 3 {
+			return nil, errors.New("usage: shift <date> <HH:MM|+-30m>")
+		}
+		shifted, err := shiftDay(day, fields[2])
+		if err != nil {
+			return nil, err
+		}
+		if err := checkReviewedDay(entries, date, shifted, report); err != nil {
+			return nil, err
+		}
+		return replaceDayEntries(entries, date, shifted), nil
+	case "length":
+		if len(fields) != 4 {
+			return nil, errors.New("usage: length <date> <block> <duration>")
+		}
+		block, err := strconv.Atoi(fields[2])
+		if err != nil || block < 1 || block > len(day) {
+			return nil, errors.New(fmt.Sprintf("block should be a number between 1 and %d", len(day)))
+		}
+		length, err := time.ParseDuration(fields[3])
+		if err != nil || length <= 0 || length%time.Minute != 0 {
+			return nil, errors.New("length should be a positive duration in whole minutes eg. 3h45m")
+		}
+		resized, err := resizeBlock(day, block-1, length)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkReviewedDay(entries, date, resized, report); err != nil {
+			return nil, err
+		}
+		return replaceDayEntries(entries, date, resized), nil
+	case "regen":
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to parse date: %v", err))
+		}
+		// continue allocation of the rest of the range, so the day doesn't skew project weights
+		rest := replaceDayEntries(entries, date, nil)
+		allocator := newProjectAllocator(schedule.Projects)
+		if err := allocator.record(rest); err != nil {
+			return nil, err
+		}
+		// regenerated day is adjusted to the rules the same way as generated days
+		regenerated, violations := compliance.enforce(date, generateDayEntries(d, rng, allocator), reviewTimeline(rest, report))
+		if len(violations) > 0 {
+			return nil, violationsError(violations)
+		}
+		if err := checkReviewedDay(entries, date, regenerated, report); err != nil {
+			return nil, err
+		}
+		return replaceDayEntries(entries, date, regenerated), nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("unknown command '%s', type 'help' for the list of commands", fields[0]))
+}
+
+// reviewTimeline summarizes logged days of the report and the reviewed entries
+func reviewTimeline(entries []Entry, report Report) map[string]workDay {
+	timeline := reportTimeline(report)
+	for _, entry := range entries {
+		if _, ok := timeline[entry.Date]; ok {
+			continue
+		}
+		if wd, err := newWorkDay(dayEntries(entries, entry.Date)); err == nil {
+			timeline[entry.Date] = wd
+		}
+	}
+
+	return timeline
+}
+
+// checkReviewedDay rejects the changed day, when it breaks compliance rules with the rest of the entries
+func checkReviewedDay(entries []Entry, date string, day []Entry, report Report) error {
+	rest := replaceDayEntries(entries, date, nil)
+	if violations := compliance.checkChanged(date, day, reviewTimeline(rest, report)); len(violations) > 0 {
+		return violationsError(violations)
+	}
+
+	return nil
+}
+
+func violationsError(violations []Violation) error {
+	var rules []string
+	for _, v := range violations {
+		rules = append(rules, fmt.Sprintf("%s: %s", v.Rule, v.Detail))
+	}
+
+	return errors.New(fmt.Sprintf("it would break %s labor law - %s", compliance.Country, strings.Join(rules, "; ")))
+}
+
+// shiftDay moves all day's entries to a new start time (HH:MM) or by a signed duration (+30m, -1h)
+func shiftDay(day []Entry, value string) ([]Entry, error) {
+	start, _, err := entryInterval(day[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var offset time.Duration
+	if strings.Contains(value, ":") {
+		newStart, err := time.ParseInLocation("2006-01-02 15:04", day[0].Date+" "+value, location)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to parse start time '%s', expected HH:MM", value))
+		}
+		offset = newStart.Sub(start)
+	} else {
+		offset, err = time.ParseDuration(value)
+		if err != nil || offset%time.Minute != 0 {
+			return nil, errors.New(fmt.Sprintf("unable to parse duration '%s', expected whole minutes eg. +30m", value))
+		}
+	}
+
+	shifted := make([]Entry, 0, len(day))
+	for _, entry := range day {
+		s, e, err := entryInterval(entry)
+		if err != nil {
+			return nil, err
+		}
+		shifted = append(shifted, withInterval(entry, s.Add(offset), e.Add(offset)))
+	}
+
+	return shifted, validateDay(shifted)
+}
+
+// resizeBlock changes length of the day's block and moves the following contiguous blocks accordingly
+func resizeBlock(day []Entry, index int, length time.Duration) ([]Entry, error) {
+	resized := make([]Entry, 0, len(day))
+	var offset time.Duration
+
+	for i, entry := range day {
+		s, e, err := entryInterval(entry)
+		if err != nil {
+			return nil, err
+		}
+		if i == index {
+			offset = length - e.Sub(s)
+			resized = append(resized, withInterval(entry, s, s.Add(length)))
+			continue
+		}
+		if i > index {
+			s, e = s.Add(offset), e.Add(offset)
+		}
+		resized = append(resized, withInterval(entry, s, e))
+	}
+
+	return resized, validateDay(resized)
+}
+
+// validateDay checks that the day's entries stay within the same day and don't overlap
+func validateDay(day []Entry) error {
+	var previousEnd time.Time
+	for _, entry := range day {
+		s, e, err := entryInterval(entry)
+		if err != nil {
+			return err
+		}
+		if s.Format("2006-01-02") != entry.Date || e.Format("2006-01-02") != entry.Date || !e.After(s) {
+			return errors.New(fmt.Sprintf("entry %s - %s does not fit into %s", entry.Start, entry.End, entry.Date))
+		}
+		if s.Before(previousEnd) {
+			return errors.New(fmt.Sprintf("entry %s - %s overlaps with the previous entry", entry.Start, entry.End))
+		}
+		previousEnd = e
+	}
+
+	return nil
+}
+
+func withInterval(entry Entry, start time.Time, end time.Time) Entry {
+	entry.Start = start.Format("15:04")
+	entry.End = end.Format("15:04")
+
+	return entry
+}
+
+// dayEntries returns entries of a single day in their original order
+func dayEntries(entries []Entry, date string) []Entry {
+	var day []Entry
+	for _, entry := range entries {
+		if entry.Date == date {
+			day = append(day, entry)
+		}
+	}
+
+	return day
+}
+
+// replaceDayEntries replaces day's entries with the new ones, keeping them at the same position
+func replaceDayEntries(entries []Entry, date string, day []Entry) []Entry {
+	var updated []Entry
+	inserted := false
+	for _, entry := range entries {
+		if entry.Date != date {
+			updated = append(updated, entry)
+			continue
+		}
+		if !inserted {
+			updated = append(updated, day...)
+			inserted = true
+		}
+	}
+
+	return updated
+}
+
+func formatReviewEntries(entries []Entry) string {
+	msg := ""
+	block := 0
+	for i, entry := range entries {
+		if i == 0 || entries[i-1].Date != entry.Date {
+			block = 0
+			msg += fmt.Sprintf("%s\n", entry.Date)
+		}
+		block++
+		msg += fmt.Sprintf("  #%d %s - %s", block, entry.Start, entry.End)
+		if entry.ProjectId > 0 {
+			msg += fmt.Sprintf(" ; Project: %d", entry.ProjectId)
+		}
+		msg += "\n"
+	}
+
+	return msg
+}
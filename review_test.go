@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reviewFixture() []Entry {
+	return []Entry{
+		{Date: "2025-03-13", Start: "08:00", End: "11:45"},
+		{Date: "2025-03-13", Start: "11:45", End: "12:15"},
+		{Date: "2025-03-13", Start: "12:15", End: "16:00"},
+		{Date: "2025-03-14", Start: "09:10", End: "12:50"},
+		{Date: "2025-03-14", Start: "12:50", End: "13:20"},
+		{Date: "2025-03-14", Start: "13:20", End: "17:00"},
+	}
+}
+
+func TestApplyReviewCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			"DropDay",
+			"drop 2025-03-13",
+			reviewFixture()[3:],
+			false,
+		},
+		{
+			"ShiftToStartTime",
+			"shift 2025-03-14 08:00",
+			append(reviewFixture()[:3], []Entry{
+				{Date: "2025-03-14", Start: "08:00", End: "11:40"},
+				{Date: "2025-03-14", Start: "11:40", End: "12:10"},
+				{Date: "2025-03-14", Start: "12:10", End: "15:50"},
+			}...),
+			false,
+		},
+		{
+			"ShiftByDuration",
+			"shift 2025-03-13 -30m",
+			append([]Entry{
+				{Date: "2025-03-13", Start: "07:30", End: "11:15"},
+				{Date: "2025-03-13", Start: "11:15", End: "11:45"},
+				{Date: "2025-03-13", Start: "11:45", End: "15:30"},
+			}, reviewFixture()[3:]...),
+			false,
+		},
+		{
+			"ChangeBlockLength",
+			"length 2025-03-13 2 45m",
+			append([]Entry{
+				{Date: "2025-03-13", Start: "08:00", End: "11:45"},
+				{Date: "2025-03-13", Start: "11:45", End: "12:30"},
+				{Date: "2025-03-13", Start: "12:30", End: "16:15"},
+			}, reviewFixture()[3:]...),
+			false,
+		},
+		{"ShiftPastMidnight", "shift 2025-03-14 +10h", nil, true},
+		{"UnknownDay", "drop 2025-03-15", nil, true},
+		{"InvalidBlock", "length 2025-03-13 4 1h", nil, true},
+		{"UnknownCommand", "move 2025-03-13", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applyReviewCommand(reviewFixture(), strings.Fields(test.command), rand.New(rand.NewSource(1)), Report{})
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("applyReviewCommand(%s) error = %v, wantErr %v", test.command, err, test.wantErr)
+				return
+			}
+			if !test.wantErr && !reflect.DeepEqual(test.want, got) {
+				t.Errorf("applyReviewCommand(%s) = %v, want %v", test.command, got, test.want)
+			}
+		})
+	}
+}
+
+func TestReviewEntries(t *testing.T) {
+	input := "drop 2025-03-13\ninvalid\nregen 2025-03-14\ndone\n"
+
+	got, err := reviewEntries(bufio.NewReader(strings.NewReader(input)), reviewFixture(), Report{})
+	if err != nil {
+		t.Fatalf("reviewEntries() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("reviewEntries() should return 2 regenerated entries, got %d", len(got))
+	}
+	for _, entry := range got {
+		if entry.Date != "2025-03-14" {
+			t.Errorf("reviewEntries() should only keep 2025-03-14, got %s", entry.Date)
+		}
+	}
+	if err := validateDay(got); err != nil {
+		t.Errorf("reviewEntries() regenerated day should be valid: %v", err)
+	}
+}
+
+func TestRegenKeepsProjectWeights(t *testing.T) {
+	originalSchedule := schedule
+	schedule = Schedule{Projects: []ProjectAllocation{{ProjectId: 1, Weight: 50}, {ProjectId: 2, Weight: 50}}}
+	t.Cleanup(func() { schedule = originalSchedule })
+	entries := []Entry{
+		{Date: "2025-03-13", Start: "08:00", End: "11:45", ProjectId: 1},
+		{Date: "2025-03-13", Start: "12:15", End: "16:00", ProjectId: 1},
+		{Date: "2025-03-14", Start: "08:00", End: "11:45", ProjectId: 1},
+		{Date: "2025-03-14", Start: "12:15", End: "16:00", ProjectId: 2},
+	}
+
+	got, err := applyReviewCommand(entries, strings.Fields("regen 2025-03-14"), rand.New(rand.NewSource(1)), Report{})
+	if err != nil {
+		t.Fatalf("applyReviewCommand() error = %v", err)
+	}
+
+	// the first day went to project 1 only, so the regenerated day should mostly go to project 2
+	minutes := make(map[int]int)
+	for _, entry := range dayEntries(got, "2025-03-14") {
+		start, end, _ := entryInterval(entry)
+		minutes[entry.ProjectId] += int(end.Sub(start).Minutes())
+	}
+	if minutes[1] >= minutes[2] {
+		t.Errorf("applyReviewCommand(regen) should favor project 2 behind its weight, got %v", minutes)
+	}
+}
+
+func TestReviewCompliance(t *testing.T) {
+	loc := useLjubljana(t)
+	originalCompliance := compliance
+	compliance, _ = resolveCompliance("SI", ComplianceConfig{})
+	t.Cleanup(func() { compliance = originalCompliance })
+	entries := []Entry{
+		{Date: "2025-03-13", Start: "08:00", End: "12:00"},
+		{Date: "2025-03-13", Start: "12:30", End: "16:30"},
+		{Date: "2025-03-14", Start: "08:00", End: "12:00"},
+		{Date: "2025-03-14", Start: "12:30", End: "16:30"},
+	}
+
+	// both leave less than 12h of rest before the following day
+	for _, command := range []string{"shift 2025-03-13 +6h", "length 2025-03-13 2 8h"} {
+		if _, err := applyReviewCommand(entries, strings.Fields(command), rand.New(rand.NewSource(1)), Report{}); err == nil {
+			t.Errorf("applyReviewCommand(%s) should reject the change breaking daily rest", command)
+		}
+	}
+	if _, err := applyReviewCommand(entries, strings.Fields("shift 2025-03-13 +1h"), rand.New(rand.NewSource(1)), Report{}); err != nil {
+		t.Errorf("applyReviewCommand(shift 2025-03-13 +1h) error = %v", err)
+	}
+
+	// regenerated day starts 12h after the day logged in BambooHR
+	start, _ := time.ParseInLocation("2006-01-02 15:04", "2025-03-13 15:00", loc)
+	end, _ := time.ParseInLocation("2006-01-02 15:04", "2025-03-13 23:00", loc)
+	report := Report{days: map[string]DayReport{"2025-03-13": {workHours: 8, start: start, end: end}}}
+	got, err := applyReviewCommand(entries[2:], strings.Fields("regen 2025-03-14"), rand.New(rand.NewSource(1)), report)
+	if err != nil {
+		t.Fatalf("applyReviewCommand(regen) error = %v", err)
+	}
+	if got[0].Start < "11:00" {
+		t.Errorf("applyReviewCommand(regen) should start after the daily rest, got %s", got[0].Start)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	RuleWeekend     = "weekend"
+	RuleLoggedHours = "hours already logged"
+	RuleHoliday     = "public holiday"
+	RuleTimeOff     = "time off"
+	RuleExcluded    = "user exclusion"
+)
+
+// DayRule is a single classification rule which applied to the date
+type DayRule struct {
+	Name string
+	// Source tells where the rule's data comes from eg. BambooHR or holidays calendar
+	Source string
+	Detail string
+}
+
+// DayDecision holds every rule which applied to the date, in the order of evaluation. Work entries are
+// generated only for days without any applied rule
+type DayDecision struct {
+	Date  time.Time
+	Rules []DayRule
+}
+
+func (d DayDecision) excluded() bool {
+	return len(d.Rules) > 0
+}
+
+// reason describes the first applied rule, or returns empty string when the day isn't excluded
+func (d DayDecision) reason() string {
+	if !d.excluded() {
+		return ""
+	}
+	rule := d.Rules[0]
+	if rule.Detail == "" {
+		return rule.Name
+	}
+
+	return rule.Name + " - " + rule.Detail
+}
+
+// evaluateDay classifies the date. It's shared by the generator, list and why commands, so they never disagree
+func evaluateDay(date time.Time, report Report) DayDecision {
+	return evaluateEmployeeDay(date, report, timeOff)
+}
+
+// evaluateEmployeeDay classifies the date of employee with the given time off, eg. a team member
+func evaluateEmployeeDay(date time.Time, report Report, timeOff map[string]string) DayDecision {
+	key := date.Format("2006-01-02")
+	decision := DayDecision{Date: date}
+
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		decision.Rules = append(decision.Rules, DayRule{Name: RuleWeekend, Source: "calendar", Detail: date.Weekday().String()})
+	}
+	if day, ok := report.days[key]; ok {
+		decision.Rules = append(decision.Rules, DayRule{Name: RuleLoggedHours, Source: "BambooHR timesheet", Detail: convertDecimalTimeToTime(day.workHours)})
+	}
+	if holiday, ok := publicHolidays[key]; ok {
+		decision.Rules = append(decision.Rules, DayRule{Name: RuleHoliday, Source: "public holidays and BambooHR company holidays", Detail: holiday})
+	}
+	if kind, ok := timeOff[key]; ok {
+		rule := DayRule{Name: RuleTimeOff, Source: "BambooHR who's out"}
+		if kind != timeOffDay {
+			rule.Detail = kind
+		}
+		decision.Rules = append(decision.Rules, rule)
+	}
+	// provided dates (PTOs, collective leave, etc.)
+	if excludedDays[key] {
+		decision.Rules = append(decision.Rules, DayRule{Name: RuleExcluded, Source: "--excludeDays"})
+	}
+
+	return decision
+}
+
+// processWhy prints every rule which applied to the date and the generator's final decision
+func processWhy(decision DayDecision) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "%s (%s)\n\n", decision.Date.Format("2006-01-02"), decision.Date.Weekday())
+	if !decision.excluded() {
+		fmt.Fprintf(w, "No exclusion rule applies\n")
+	} else {
+		// table header
+		fmt.Fprintf(w, "Rule\tSource\tDetail\t\n")
+		for _, rule := range decision.Rules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", rule.Name, rule.Source, rule.Detail)
+		}
+	}
+
+	if decision.excluded() {
+		fmt.Fprintf(w, "\nDecision: skipped because of %s \n", decision.reason())
+		return
+	}
+	fmt.Fprintf(w, "\nDecision: work entries are generated \n")
+}
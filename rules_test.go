@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEvaluateDay(t *testing.T) {
+	originalHolidays, originalTimeOff, originalExcluded := publicHolidays, timeOff, excludedDays
+	publicHolidays = map[string]string{"2024-12-25": "Božič", "2024-12-26": "Dan samostojnosti in enotnosti"}
+	timeOff = map[string]string{"2024-12-26": timeOffDay, "2024-12-27": timeOffDay}
+	excludedDays = map[string]bool{"2024-12-30": true}
+	t.Cleanup(func() { publicHolidays, timeOff, excludedDays = originalHolidays, originalTimeOff, originalExcluded })
+
+	report := Report{map[string]DayReport{"2024-12-23": {workHours: 8}, "2024-12-28": {workHours: 2}}, 10}
+
+	tests := []struct {
+		date       string
+		wantRules  []string
+		wantReason string
+	}{
+		{"2024-12-23", []string{RuleLoggedHours}, "hours already logged - 8 hours and 0 minutes"},
+		{"2024-12-24", nil, ""},
+		{"2024-12-25", []string{RuleHoliday}, "public holiday - Božič"},
+		{"2024-12-26", []string{RuleHoliday, RuleTimeOff}, "public holiday - Dan samostojnosti in enotnosti"},
+		{"2024-12-27", []string{RuleTimeOff}, "time off"},
+		{"2024-12-28", []string{RuleWeekend, RuleLoggedHours}, "weekend - Saturday"},
+		{"2024-12-30", []string{RuleExcluded}, "user exclusion"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.date, func(t *testing.T) {
+			date, _ := time.Parse("2006-01-02", test.date)
+
+			decision := evaluateDay(date, report)
+
+			var rules []string
+			for _, rule := range decision.Rules {
+				rules = append(rules, rule.Name)
+			}
+			if !reflect.DeepEqual(test.wantRules, rules) {
+				t.Errorf("evaluateDay() rules = %v, want %v", rules, test.wantRules)
+			}
+			if decision.excluded() != (len(test.wantRules) > 0) {
+				t.Errorf("evaluateDay() excluded = %v, want %v", decision.excluded(), len(test.wantRules) > 0)
+			}
+			if decision.reason() != test.wantReason {
+				t.Errorf("evaluateDay() reason = %q, want %q", decision.reason(), test.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+const (
+	DayTracked  = "tracked"
+	DayMissing  = "missing"
+	DayWeekend  = "weekend"
+	DayHoliday  = "holiday"
+	DayTimeOff  = "timeOff"
+	DayExcluded = "excluded"
+)
+
+// serveMu serializes API requests, because operations share the date range and days off with the CLI globals
+var serveMu sync.Mutex
+
+// CalendarDay is a single day of the month calendar
+type CalendarDay struct {
+	Date    string  `json:"date"`
+	Weekday string  `json:"weekday"`
+	Status  string  `json:"status"`
+	Hours   float64 `json:"hours"`
+	Start   string  `json:"start,omitempty"`
+	End     string  `json:"end,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+// RequiredPeriod holds required hours of a single period, returned by the required hours API
+type RequiredPeriod struct {
+	Period       string `json:"period"`
+	WorkDays     int    `json:"workDays"`
+	WorkHours    int    `json:"workHours"`
+	Holidays     int    `json:"holidays"`
+	HolidayHours int    `json:"holidayHours"`
+	TotalHours   int    `json:"totalHours"`
+}
+
+// BalanceResponse is TeamBalance of the employee, with durations in hours
+type BalanceResponse struct {
+	Tracked  float64  `json:"tracked"`
+	Required float64  `json:"required"`
+	Balance  float64  `json:"balance"`
+	Missing  []string `json:"missing"`
+}
+
+// processServe starts local web dashboard with JSON API over the CLI operations
+func processServe(addr string) {
+	fmt.Printf("Serving dashboard on http://%s, press Ctrl+c to stop \n", addr)
+	if err := http.ListenAndServe(addr, newServeMux()); err != nil {
+		fmt.Printf("Unable to start server: %v. Aborting \n", err)
+		os.Exit(1)
+	}
+}
+
+func newServeMux() http.Handler {
+	static, _ := fs.Sub(webFiles, "web")
+
+	mux := http.NewServeMux()
+	// dashboard is a single page
+	mux.Handle("GET /{$}", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/list", handleList)
+	mux.HandleFunc("GET /api/required", handleRequired)
+	mux.HandleFunc("GET /api/balance", handleBalance)
+	mux.HandleFunc("GET /api/preview", handlePreview)
+	mux.HandleFunc("POST /api/submit", handleSubmit)
+
+	return localOnly(mux)
+}
+
+// localOnly rejects requests for other hosts than the server's own address, so pages of other domains can't reach
+// the API through DNS rebinding
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isServerHost(r.Host) {
+			writeJsonError(w, http.StatusForbidden, errors.New(fmt.Sprintf("unexpected host '%s'", r.Host)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isServerHost checks whether the Host header is the address the server listens on or a loopback address
+func isServerHost(hostPort string) bool {
+	if hostPort == addr {
+		return true
+	}
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host = hostPort
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkSameOrigin rejects cross-site requests. Browsers send form posts as text/plain without CORS preflight, so only
+// JSON from the dashboard's own origin is accepted
+func checkSameOrigin(r *http.Request) (int, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return http.StatusUnsupportedMediaType, errors.New("content type should be application/json")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return http.StatusForbidden, errors.New(fmt.Sprintf("request from origin '%s' isn't allowed", origin))
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// loadRange fetches tracked hours and days off of the range given by 'month', 'week' or 'start' and 'end' query
+// parameters, the end date is exclusive
+func loadRange(query url.Values) (Report, time.Time, time.Time, error) {
+	start, end, err := resolveDateRange(query.Get("start"), query.Get("end"), query.Get("month"), query.Get("week"), false)
+	if err != nil {
+		return Report{}, start, end, err
+	}
+	startDate = start.Format("2006-01-02")
+	endDate = end.Format("2006-01-02")
+
+	workingHours, err := fetchWorkingHours()
+	if err != nil {
+		return Report{}, start, end, errors.New(fmt.Sprintf("failed fetching working hours: %v", err))
+	}
+	location, err = resolveLocation(timezone, workingHours)
+	if err != nil {
+		return Report{}, start, end, err
+	}
+	// holidays are the same as in the required hours view, employee's time off is added on top of them
+	publicHolidays, err = requiredHolidays(start, end)
+	if err != nil {
+		return Report{}, start, end, errors.New(fmt.Sprintf("cannot load holidays: %v", err))
+	}
+	timeOff = make(map[string]string)
+	if employeeId > 0 {
+		timeOff, err = NewCsvHolidays("slovenian_public_work_off_days.csv").fetchTimeOff()
+		if err != nil {
+			return Report{}, start, end, errors.New(fmt.Sprintf("cannot load time off: %v", err))
+		}
+	}
+	holidays = combineDaysOff(publicHolidays, timeOff)
+
+	return groupHoursByDate(workingHours), start, end, nil
+}
+
+// calendarDays classifies every day of the range for the month calendar
+func calendarDays(report Report, start time.Time, end time.Time) []CalendarDay {
+	var days []CalendarDay
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		day := CalendarDay{Date: date, Weekday: d.Weekday().String(), Status: DayMissing}
+		decision := evaluateDay(d, report)
+		if logged, ok := report.days[date]; ok {
+			day.Status, day.Hours = DayTracked, logged.workHours
+			day.Start, day.End = formatLocalTime(logged.start), formatLocalTime(logged.end)
+		} else if decision.excluded() {
+			day.Reason = decision.reason()
+			switch decision.Rules[0].Name {
+			case RuleWeekend:
+				day.Status = DayWeekend
+			case RuleHoliday:
+				day.Status = DayHoliday
+			case RuleTimeOff:
+				day.Status = DayTimeOff
+			default:
+				day.Status = DayExcluded
+			}
+		}
+		days = append(days, day)
+	}
+
+	return days
+}
+
+func handleList(w http.ResponseWriter, r *http.Request) {
+	serveMu.Lock()
+	defer serveMu.Unlock()
+
+	report, start, end, err := loadRange(r.URL.Query())
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJson(w, http.StatusOK, calendarDays(report, start, end))
+}
+
+func handleRequired(w http.ResponseWriter, r *http.Request) {
+	serveMu.Lock()
+	defer serveMu.Unlock()
+
+	query := r.URL.Query()
+	var year int
+	if query.Get("year") != "" {
+		var err error
+		year, err = strconv.Atoi(query.Get("year"))
+		if err != nil || year <= 0 {
+			writeJsonError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("invalid 'year' provided: %s", query.Get("year"))))
+			return
+		}
+	}
+	start, end, err := resolveRequiredRange(year, query.Get("start"), query.Get("end"), query.Get("month"), query.Get("week"), false)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	calendar, err := requiredHolidays(start, end)
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, errors.New(fmt.Sprintf("cannot load holidays: %v", err)))
+		return
+	}
+	groupBy := query.Get("groupBy")
+	if groupBy == "" {
+		groupBy = GroupByMonth
+	}
+	report, err := getRequiredHoursInRange(start, end, groupBy, calendar)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	periods := make([]RequiredPeriod, 0, len(report))
+	for period, r := range report {
+		periods = append(periods, RequiredPeriod{period, r.workDays, r.workHours, r.holidays, r.totalHolidayHours, r.totalHours})
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Period < periods[j].Period })
+
+	writeJson(w, http.StatusOK, periods)
+}
+
+func handleBalance(w http.ResponseWriter, r *http.Request) {
+	serveMu.Lock()
+	defer serveMu.Unlock()
+
+	report, start, end, err := loadRange(r.URL.Query())
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	balance := calculateBalance(TeamMember{Id: employeeId}, report, timeOff, start, end)
+
+	writeJson(w, http.StatusOK, BalanceResponse{
+		Tracked:  balance.Tracked.Hours(),
+		Required: balance.Required.Hours(),
+		Balance:  (balance.Tracked - balance.Required).Hours(),
+		Missing:  balance.Missing,
+	})
+}
+
+// handlePreview generates entries for missing days of the range without submitting them
+func handlePreview(w http.ResponseWriter, r *http.Request) {
+	serveMu.Lock()
+	defer serveMu.Unlock()
+
+	report, _, _, err := loadRange(r.URL.Query())
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	blockSource, err = newBlockSource(generator)
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, errors.New(fmt.Sprintf("unable to prepare '%s' generator: %v", generator, err)))
+		return
+	}
+	entries, err := generateWorkEntries(report, startDate, endDate)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJson(w, http.StatusOK, append([]Entry{}, entries...))
+}
+
+// handleSubmit validates entries eg. from the preview the same way as imported entries, and pushes them to BambooHR
+func handleSubmit(w http.ResponseWriter, r *http.Request) {
+	if status, err := checkSameOrigin(r); err != nil {
+		writeJsonError(w, status, err)
+		return
+	}
+
+	serveMu.Lock()
+	defer serveMu.Unlock()
+
+	if offline {
+		writeJsonError(w, http.StatusServiceUnavailable, errors.New("entries aren't pushed to BambooHR in offline mode"))
+		return
+	}
+	var body TimeEntriesPostBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJsonError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("unable to unmarshal json: %v", err)))
+		return
+	}
+	start, end, err := importRange(body.Entries)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	report, _, _, err := loadRange(url.Values{"start": {start}, "end": {end}})
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	entries, err := validateImportedEntries(body.Entries, report, false)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := storeEntries(entries); err != nil {
+		writeJsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJson(w, http.StatusOK, entries)
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeJsonError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mluksic/bamboo/fakebamboo"
+)
+
+func TestCalendarDays(t *testing.T) {
+	useLjubljana(t)
+	originalHolidays, originalTimeOff, originalExcluded := publicHolidays, timeOff, excludedDays
+	publicHolidays = map[string]string{"2024-12-25": "Božič"}
+	timeOff = map[string]string{"2024-12-27": "Vacation"}
+	excludedDays = map[string]bool{"2024-12-24": true}
+	t.Cleanup(func() { publicHolidays, timeOff, excludedDays = originalHolidays, originalTimeOff, originalExcluded })
+
+	start := time.Date(2024, 12, 23, 8, 0, 0, 0, location)
+	report := groupHoursByDate([]TimeEntry{{Date: "2024-12-23", Start: start, End: start.Add(8 * time.Hour), Hours: 8}})
+
+	got := calendarDays(report, time.Date(2024, 12, 23, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC))
+
+	want := []CalendarDay{
+		{Date: "2024-12-23", Weekday: "Monday", Status: DayTracked, Hours: 8, Start: "08:00", End: "16:00"},
+		{Date: "2024-12-24", Weekday: "Tuesday", Status: DayExcluded, Reason: "user exclusion"},
+		{Date: "2024-12-25", Weekday: "Wednesday", Status: DayHoliday, Reason: "public holiday - Božič"},
+		{Date: "2024-12-26", Weekday: "Thursday", Status: DayMissing},
+		{Date: "2024-12-27", Weekday: "Friday", Status: DayTimeOff, Reason: "time off - Vacation"},
+		{Date: "2024-12-28", Weekday: "Saturday", Status: DayWeekend, Reason: "weekend - Saturday"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("calendarDays() = %v, want %v", got, want)
+	}
+}
+
+func TestServeRequired(t *testing.T) {
+	server := httptest.NewServer(newServeMux())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/required?month=2025-01")
+	if err != nil {
+		t.Fatalf("GET /api/required error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got []RequiredPeriod
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	want := []RequiredPeriod{{Period: "2025-01", WorkDays: 21, WorkHours: 168, Holidays: 2, HolidayHours: 16, TotalHours: 184}}
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(want, got) {
+		t.Errorf("GET /api/required = %d %v, want 200 %v", resp.StatusCode, got, want)
+	}
+}
+
+var jsonHeaders = map[string]string{"Content-Type": "application/json"}
+
+func TestServeErrors(t *testing.T) {
+	server := httptest.NewServer(newServeMux())
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{"InvalidMonth", http.MethodGet, "/api/required?month=2025-13", "", nil, http.StatusBadRequest},
+		{"InvalidGrouping", http.MethodGet, "/api/required?month=2025-01&groupBy=day", "", nil, http.StatusBadRequest},
+		{"YearAndMonth", http.MethodGet, "/api/required?year=2025&month=2025-01", "", nil, http.StatusBadRequest},
+		{"InvalidSubmitBody", http.MethodPost, "/api/submit", "not json", jsonHeaders, http.StatusBadRequest},
+		{"EmptySubmit", http.MethodPost, "/api/submit", `{"entries":[]}`, jsonHeaders, http.StatusBadRequest},
+		{"SubmitRequiresPost", http.MethodGet, "/api/submit", "", nil, http.StatusMethodNotAllowed},
+		{"SubmitRequiresJson", http.MethodPost, "/api/submit", `{"entries":[]}`, map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"SameOriginSubmit", http.MethodPost, "/api/submit", `{"entries":[]}`, map[string]string{"Content-Type": "application/json; charset=utf-8", "Origin": server.URL}, http.StatusBadRequest},
+		{"CrossOriginSubmit", http.MethodPost, "/api/submit", `{"entries":[]}`, map[string]string{"Content-Type": "application/json", "Origin": "https://evil.example.com"}, http.StatusForbidden},
+		{"ForeignHost", http.MethodGet, "/api/required?month=2025-01", "", map[string]string{"Host": "evil.example.com:8080"}, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			// Host header is taken from the field, not the headers
+			if host, ok := test.headers["Host"]; ok {
+				req.Host = host
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s error = %v", test.method, test.path, err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Errorf("%s %s = %d, want %d", test.method, test.path, resp.StatusCode, test.status)
+			}
+		})
+	}
+}
+
+func TestServeDashboard(t *testing.T) {
+	server := httptest.NewServer(newServeMux())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("GET / = %d %s, want 200 text/html", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestServeHolidaysAgree(t *testing.T) {
+	useCacheDir(t)
+	fake, err := fakebamboo.New(fakebamboo.Seed{
+		Timezone: "Europe/Ljubljana",
+		TimeOff: []fakebamboo.TimeOff{
+			{Id: 1, Type: "holiday", Name: "Company Day", Start: "2025-03-12", End: "2025-03-12"},
+			{Id: 2, Type: "timeOff", EmployeeId: 12, Name: "Vacation", Start: "2025-03-13", End: "2025-03-13"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("fakebamboo.New() error = %v", err)
+	}
+	bamboo := httptest.NewServer(fake)
+	defer bamboo.Close()
+	originalBaseUrl, originalApiKey, originalEmployeeId, originalTimezone := baseUrl, apiKey, employeeId, timezone
+	baseUrl, apiKey, employeeId, timezone = bamboo.URL+fakebamboo.BasePath, "key", 12, "Europe/Ljubljana"
+	t.Cleanup(func() {
+		baseUrl, apiKey, employeeId, timezone = originalBaseUrl, originalApiKey, originalEmployeeId, originalTimezone
+	})
+	useLjubljana(t)
+	server := httptest.NewServer(newServeMux())
+	defer server.Close()
+
+	get := func(path string, v any) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", path, resp.StatusCode)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("unable to decode response: %v", err)
+		}
+	}
+
+	var required []RequiredPeriod
+	get("/api/required?month=2025-03", &required)
+	var balance BalanceResponse
+	get("/api/balance?month=2025-03", &balance)
+	var days []CalendarDay
+	get("/api/list?month=2025-03", &days)
+
+	// company holiday is excluded from both views, employee's time off only from the balance
+	if len(required) != 1 || required[0].WorkHours != 160 {
+		t.Errorf("GET /api/required = %v, want 160 work hours", required)
+	}
+	if balance.Required != 152 {
+		t.Errorf("GET /api/balance required = %v, want 152", balance.Required)
+	}
+	if len(days) != 31 || days[11].Status != DayHoliday || days[12].Status != DayTimeOff {
+		t.Errorf("GET /api/list = %v, want company holiday and time off", days)
+	}
+}
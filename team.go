@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// max number of employees whose entries are fetched in a single request
+const employeesPerRequest = 25
+
+// max number of timesheet requests running at the same time
+const maxParallelRequests = 4
+
+// TeamMember is an employee reported on in team mode
+type TeamMember struct {
+	Id   int
+	Name string
+}
+
+// label returns member's name, or ID when the name is unknown
+func (m TeamMember) label() string {
+	if m.Name == "" {
+		return strconv.Itoa(m.Id)
+	}
+
+	return m.Name
+}
+
+// TeamBalance compares employee's tracked hours with hours required between start and end date
+type TeamBalance struct {
+	Member   TeamMember
+	Tracked  time.Duration
+	Required time.Duration
+	// Missing holds workdays without any logged hours
+	Missing []string
+}
+
+// loadTeam returns members from the roster file and comma-separated employee IDs, without duplicates
+func loadTeam(employeeIds string, roster string) ([]TeamMember, error) {
+	var members []TeamMember
+	if roster != "" {
+		file, err := os.Open(roster)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to open roster file: %v", err))
+		}
+		defer file.Close()
+
+		members, err = readRoster(file)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid roster file '%s': %v", roster, err))
+		}
+	}
+
+	ids, err := parseEmployeeIds(employeeIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(members, func(m TeamMember) bool { return m.Id == id }) {
+			members = append(members, TeamMember{Id: id})
+		}
+	}
+
+	return members, nil
+}
+
+// parseEmployeeIds parses comma-separated list of employee IDs eg. 12,34
+func parseEmployeeIds(value string) ([]int, error) {
+	var ids []int
+	if value == "" {
+		return ids, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, errors.New(fmt.Sprintf("invalid employee ID '%s'", part))
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// readRoster reads CSV file with 'id' and optional 'name' columns
+func readRoster(reader io.Reader) ([]TeamMember, error) {
+	table, err := readCsvTable(reader, ',', "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var members []TeamMember
+	for i, row := range table.rows {
+		id, err := strconv.Atoi(table.value(row, "id"))
+		if err != nil || id <= 0 {
+			return nil, errors.New(fmt.Sprintf("line %d: invalid employee ID '%s'", table.line(i), table.value(row, "id")))
+		}
+		if slices.ContainsFunc(members, func(m TeamMember) bool { return m.Id == id }) {
+			return nil, errors.New(fmt.Sprintf("line %d: duplicated employee ID %d", table.line(i), id))
+		}
+		members = append(members, TeamMember{Id: id, Name: table.value(row, "name")})
+	}
+
+	return members, nil
+}
+
+// fetchTeamHours fetches entries of members in batches, while running a bounded number of requests in parallel
+func fetchTeamHours(members []TeamMember) ([]TimeEntry, error) {
+	var batches [][]int
+	for batch := range slices.Chunk(members, employeesPerRequest) {
+		ids := make([]int, 0, len(batch))
+		for _, member := range batch {
+			ids = append(ids, member.Id)
+		}
+		batches = append(batches, ids)
+	}
+
+	results := make([][]TimeEntry, len(batches))
+	errs := make([]error, len(batches))
+	semaphore := make(chan struct{}, maxParallelRequests)
+	var wg sync.WaitGroup
+	for i, ids := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i], errs[i] = fetchEmployeesHours(ids)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// keep batch order, so the output doesn't depend on which request finished first
+	return slices.Concat(results...), nil
+}
+
+// groupHoursByEmployee returns report of every member, members without entries get an empty report
+func groupHoursByEmployee(members []TeamMember, entries []TimeEntry) map[int]Report {
+	byEmployee := make(map[int][]TimeEntry)
+	for _, entry := range entries {
+		byEmployee[entry.EmployeeId] = append(byEmployee[entry.EmployeeId], entry)
+	}
+
+	reports := make(map[int]Report)
+	for _, member := range members {
+		reports[member.Id] = groupHoursByDate(byEmployee[member.Id])
+	}
+
+	return reports
+}
+
+// calculateBalance requires daily target on every day, which isn't weekend, public holiday or member's time off
+func calculateBalance(member TeamMember, report Report, timeOff map[string]string, start time.Time, end time.Time) TeamBalance {
+	balance := TeamBalance{Member: member, Tracked: hoursToDuration(report.totalWorkHours)}
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if !requiresWork(evaluateEmployeeDay(d, report, timeOff)) {
+			continue
+		}
+		balance.Required += dailyTarget
+		if _, ok := report.days[d.Format("2006-01-02")]; !ok {
+			balance.Missing = append(balance.Missing, d.Format("2006-01-02"))
+		}
+	}
+
+	return balance
+}
+
+// requiresWork tells whether daily target applies to the day. User exclusions are ignored, because they're
+// meant for the user's own PTO days
+func requiresWork(decision DayDecision) bool {
+	for _, rule := range decision.Rules {
+		if rule.Name == RuleWeekend || rule.Name == RuleHoliday || rule.Name == RuleTimeOff {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hoursToDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+}
+
+// processBalance prints tracked vs. required hours and missing days of every employee
+func processBalance(balances []TeamBalance) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	defer w.Flush()
+	// table header
+	fmt.Fprintf(w, "Employee\tTracked\tRequired\tBalance\tMissing days\t\n")
+
+	for _, balance := range balances {
+		diff := balance.Tracked - balance.Required
+		sign := "+"
+		if diff < 0 {
+			sign, diff = "-", -diff
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s%s\t%d\t\n", balance.Member.label(), formatDuration(balance.Tracked), formatDuration(balance.Required),
+			sign, formatDuration(diff), len(balance.Missing))
+	}
+
+	for _, balance := range balances {
+		if len(balance.Missing) > 0 {
+			fmt.Fprintf(w, "\n%s is missing: %s", balance.Member.label(), strings.Join(balance.Missing, ", "))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// processTeamList prints daily hours of every member side by side, followed by their balance
+func processTeamList(members []TeamMember, reports map[int]Report, timeOffs map[int]map[string]string, start time.Time, end time.Time) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 5, ' ', 0)
+	// table header
+	fmt.Fprintf(w, "Date\tWeekday\t")
+	for _, member := range members {
+		fmt.Fprintf(w, "%s\t", member.label())
+	}
+	fmt.Fprintf(w, "\n")
+
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		fmt.Fprintf(w, "%s\t%s\t", date, d.Weekday())
+		for _, member := range members {
+			report := reports[member.Id]
+			if day, ok := report.days[date]; ok {
+				fmt.Fprintf(w, "%s\t", formatDuration(hoursToDuration(day.workHours)))
+				continue
+			}
+			status := "MISSING"
+			if decision := evaluateEmployeeDay(d, report, timeOffs[member.Id]); !requiresWork(decision) {
+				status = decision.Rules[0].Name
+			}
+			fmt.Fprintf(w, "%s\t", status)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	w.Flush()
+
+	fmt.Println()
+	processBalance(teamBalances(members, reports, timeOffs, start, end))
+}
+
+// teamBalances calculates balance of every member in the roster order
+func teamBalances(members []TeamMember, reports map[int]Report, timeOffs map[int]map[string]string, start time.Time, end time.Time) []TeamBalance {
+	balances := make([]TeamBalance, 0, len(members))
+	for _, member := range members {
+		balances = append(balances, calculateBalance(member, reports[member.Id], timeOffs[member.Id], start, end))
+	}
+
+	return balances
+}
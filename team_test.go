@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEmployeeIds(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"Empty", "", nil, false},
+		{"Single", "12", []int{12}, false},
+		{"Multiple", "12, 34,56", []int{12, 34, 56}, false},
+		{"Duplicates", "12,34,12", []int{12, 34}, false},
+		{"InvalidId", "12,abc", nil, true},
+		{"TrailingComma", "12,", nil, true},
+		{"NegativeId", "-5", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseEmployeeIds(test.input)
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseEmployeeIds() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("parseEmployeeIds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReadRoster(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []TeamMember
+		wantErr bool
+	}{
+		{
+			"WithNames",
+			"id,name\n12,Jane Doe\n34,John Smith\n",
+			[]TeamMember{{Id: 12, Name: "Jane Doe"}, {Id: 34, Name: "John Smith"}},
+			false,
+		},
+		{"WithoutNames", "ID\n12\n34\n", []TeamMember{{Id: 12}, {Id: 34}}, false},
+		{"MissingIdColumn", "name\nJane Doe\n", nil, true},
+		{"InvalidId", "id,name\nabc,Jane Doe\n", nil, true},
+		{"DuplicatedId", "id,name\n12,Jane Doe\n12,John Smith\n", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readRoster(strings.NewReader(test.input))
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("readRoster() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("readRoster() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCalculateBalance(t *testing.T) {
+	originalHolidays, originalExcluded := publicHolidays, excludedDays
+	publicHolidays = map[string]string{"2024-12-25": "Božič", "2024-12-26": "Dan samostojnosti in enotnosti"}
+	// user's own exclusions don't apply to team members
+	excludedDays = map[string]bool{"2024-12-24": true}
+	t.Cleanup(func() { publicHolidays, excludedDays = originalHolidays, originalExcluded })
+
+	start := time.Date(2024, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+	report := groupHoursByDate([]TimeEntry{
+		{EmployeeId: 12, Date: "2024-12-23", Hours: 8.5},
+		{EmployeeId: 12, Date: "2024-12-28", Hours: 2},
+	})
+	timeOff := map[string]string{"2024-12-27": "Vacation"}
+
+	got := calculateBalance(TeamMember{Id: 12, Name: "Jane Doe"}, report, timeOff, start, end)
+
+	want := TeamBalance{
+		Member:   TeamMember{Id: 12, Name: "Jane Doe"},
+		Tracked:  10*time.Hour + 30*time.Minute,
+		Required: 16 * time.Hour,
+		Missing:  []string{"2024-12-24"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("calculateBalance() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupHoursByEmployee(t *testing.T) {
+	members := []TeamMember{{Id: 12}, {Id: 34}}
+	entries := []TimeEntry{
+		{EmployeeId: 12, Date: "2024-12-23", Hours: 4},
+		{EmployeeId: 12, Date: "2024-12-23", Hours: 4},
+		{EmployeeId: 56, Date: "2024-12-23", Hours: 8},
+	}
+
+	got := groupHoursByEmployee(members, entries)
+
+	if len(got) != 2 {
+		t.Fatalf("groupHoursByEmployee() returned %d reports, want 2", len(got))
+	}
+	if got[12].totalWorkHours != 8 || got[12].days["2024-12-23"].workHours != 8 {
+		t.Errorf("groupHoursByEmployee() report of 12 = %v, want 8 hours on 2024-12-23", got[12])
+	}
+	if got[34].totalWorkHours != 0 || len(got[34].days) != 0 {
+		t.Errorf("groupHoursByEmployee() report of 34 = %v, want empty report", got[34])
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatBamboo   = "bamboo"
+	FormatToggl    = "toggl"
+	FormatClockify = "clockify"
+	FormatHarvest  = "harvest"
+)
+
+var importFormats = []string{FormatBamboo, FormatToggl, FormatClockify, FormatHarvest}
+
+// Harvest exports only durations, so the day's entries are laid out back to back from this time
+const harvestDayStart = "08:00"
+
+// date and time layouts used by time-tracker exports, depending on the user's locale settings
+var (
+	trackerDateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+	trackerTimeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM", "3:04PM"}
+)
+
+// readTrackerEntries reads detailed CSV export of a third-party time tracker
+func readTrackerEntries(reader io.Reader, format string) ([]Entry, error) {
+	switch format {
+	case FormatToggl:
+		return readTogglEntries(reader)
+	case FormatClockify:
+		return readClockifyEntries(reader)
+	case FormatHarvest:
+		return readHarvestEntries(reader)
+	}
+
+	return nil, errors.New(fmt.Sprintf("unsupported import format '%s', use one of: %s", format, strings.Join(importFormats, ", ")))
+}
+
+// readTogglEntries reads Toggl Track detailed report CSV export
+func readTogglEntries(reader io.Reader) ([]Entry, error) {
+	table, err := readCsvTable(reader, ',', "Start date", "Start time", "End date", "End time")
+	if err != nil {
+		return nil, err
+	}
+
+	return readIntervalRows(table, "Start date", "Start time", "End date", "End time", "Description")
+}
+
+// readClockifyEntries reads Clockify detailed report CSV export
+func readClockifyEntries(reader io.Reader) ([]Entry, error) {
+	table, err := readCsvTable(reader, ',', "Start Date", "Start Time", "End Date", "End Time")
+	if err != nil {
+		return nil, err
+	}
+
+	return readIntervalRows(table, "Start Date", "Start Time", "End Date", "End Time", "Description")
+}
+
+// readIntervalRows converts rows with start and end date and time into entries
+func readIntervalRows(table *csvTable, startDateCol string, startTimeCol string, endDateCol string, endTimeCol string, noteCol string) ([]Entry, error) {
+	var entries []Entry
+	for i, row := range table.rows {
+		start, err := parseTrackerDateTime(table.value(row, startDateCol), table.value(row, startTimeCol))
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %v", table.line(i), err))
+		}
+		end, err := parseTrackerDateTime(table.value(row, endDateCol), table.value(row, endTimeCol))
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %v", table.line(i), err))
+		}
+		if start.Format("2006-01-02") != end.Format("2006-01-02") {
+			return nil, errors.New(fmt.Sprintf("line %d: entries spanning midnight are not supported", table.line(i)))
+		}
+
+		entries = append(entries, Entry{
+			Date:  start.Format("2006-01-02"),
+			Start: start.Format("15:04"),
+			End:   end.Format("15:04"),
+			Note:  table.value(row, noteCol),
+		})
+	}
+
+	return entries, nil
+}
+
+// readHarvestEntries reads Harvest detailed time report CSV export. Harvest only exports durations, so the
+// day's entries are placed one after another, starting at 08:00
+func readHarvestEntries(reader io.Reader) ([]Entry, error) {
+	table, err := readCsvTable(reader, ',', "Date", "Hours")
+	if err != nil {
+		return nil, err
+	}
+
+	dayEnds := make(map[string]time.Time)
+	var entries []Entry
+	for i, row := range table.rows {
+		date, err := parseTrackerDate(table.value(row, "Date"))
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %v", table.line(i), err))
+		}
+		hours, err := strconv.ParseFloat(table.value(row, "Hours"), 64)
+		if err != nil || hours < 0 {
+			return nil, errors.New(fmt.Sprintf("line %d: invalid hours '%s'", table.line(i), table.value(row, "Hours")))
+		}
+
+		day := date.Format("2006-01-02")
+		start, ok := dayEnds[day]
+		if !ok {
+			start, _ = time.ParseInLocation("2006-01-02 15:04", day+" "+harvestDayStart, location)
+		}
+		end := start.Add(time.Duration(hours * float64(time.Hour))).Round(time.Minute)
+		dayEnds[day] = end
+
+		entries = append(entries, Entry{
+			Date:  day,
+			Start: start.Format("15:04"),
+			End:   end.Format("15:04"),
+			Note:  table.value(row, "Notes"),
+		})
+	}
+
+	return entries, nil
+}
+
+func parseTrackerDate(value string) (time.Time, error) {
+	for _, layout := range trackerDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, errors.New(fmt.Sprintf("unable to parse date '%s'", value))
+}
+
+func parseTrackerDateTime(date string, clock string) (time.Time, error) {
+	d, err := parseTrackerDate(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range trackerTimeLayouts {
+		if t, err := time.Parse(layout, strings.ToUpper(clock)); err == nil {
+			return time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), t.Second(), 0, location), nil
+		}
+	}
+
+	return time.Time{}, errors.New(fmt.Sprintf("unable to parse time '%s'", clock))
+}
+
+// roundEntries rounds start and end of entries to the nearest step in minutes, dropping entries which
+// end up empty
+func roundEntries(entries []Entry, step int) ([]Entry, error) {
+	if step <= 0 {
+		return entries, nil
+	}
+
+	var rounded []Entry
+	for _, entry := range entries {
+		start, end, err := entryInterval(entry)
+		if err != nil {
+			return nil, err
+		}
+		start = roundToStep(start, step)
+		end = roundToStep(end, step)
+		if !end.After(start) {
+			continue
+		}
+		rounded = append(rounded, withInterval(entry, start, end))
+	}
+
+	return rounded, nil
+}
+
+// roundToStep rounds time to the nearest step in minutes since midnight
+func roundToStep(t time.Time, step int) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	minutes := int(t.Sub(midnight).Minutes())
+	minutes = (minutes + step/2) / step * step
+
+	return midnight.Add(time.Duration(minutes) * time.Minute)
+}
+
+// mergeEntries merges touching or overlapping entries of the same day and project into a single entry
+func mergeEntries(entries []Entry) ([]Entry, error) {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b Entry) int {
+		return strings.Compare(a.Date+a.Start, b.Date+b.Start)
+	})
+
+	var merged []Entry
+	for _, entry := range sorted {
+		if len(merged) == 0 {
+			merged = append(merged, entry)
+			continue
+		}
+
+		last := &merged[len(merged)-1]
+		_, lastEnd, err := entryInterval(*last)
+		if err != nil {
+			return nil, err
+		}
+		start, end, err := entryInterval(entry)
+		if err != nil {
+			return nil, err
+		}
+		if last.Date != entry.Date || last.ProjectId != entry.ProjectId || last.TaskId != entry.TaskId || start.After(lastEnd) {
+			merged = append(merged, entry)
+			continue
+		}
+
+		if end.After(lastEnd) {
+			last.End = entry.End
+		}
+		if entry.Note != "" && !slices.Contains(strings.Split(last.Note, "; "), entry.Note) {
+			last.Note = strings.TrimPrefix(last.Note+"; "+entry.Note, "; ")
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadTrackerEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		records string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			"Toggl",
+			FormatToggl,
+			"\ufeffUser,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+				"Jane,jane@example.com,,Website,,Code review,No,2025-03-13,08:02:11,2025-03-13,09:15:40,01:13:29,,\n" +
+				"Jane,jane@example.com,,Website,,Standup,No,2025-03-13,09:15:40,2025-03-13,09:30:00,00:14:20,,\n",
+			[]Entry{
+				{Date: "2025-03-13", Start: "08:02", End: "09:15", Note: "Code review"},
+				{Date: "2025-03-13", Start: "09:15", End: "09:30", Note: "Standup"},
+			},
+			false,
+		},
+		{
+			"Clockify",
+			FormatClockify,
+			"Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+				"Website,,Planning,,Jane,,jane@example.com,,No,03/13/2025,08:00:00 AM,03/13/2025,12:30:00 PM,04:30:00,4.50\n",
+			[]Entry{{Date: "2025-03-13", Start: "08:00", End: "12:30", Note: "Planning"}},
+			false,
+		},
+		{
+			"Harvest",
+			FormatHarvest,
+			"Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded,Billable?,Invoiced?,First Name,Last Name\n" +
+				"2025-03-13,Acme,Website,,Development,API,3.5,3.5,Yes,No,Jane,Doe\n" +
+				"2025-03-13,Acme,Website,,Meetings,,0.75,0.75,Yes,No,Jane,Doe\n",
+			[]Entry{
+				{Date: "2025-03-13", Start: "08:00", End: "11:30", Note: "API"},
+				{Date: "2025-03-13", Start: "11:30", End: "12:15"},
+			},
+			false,
+		},
+		{
+			"SpanningMidnight",
+			FormatToggl,
+			"Description,Start date,Start time,End date,End time\nRelease,2025-03-13,23:00:00,2025-03-14,01:00:00\n",
+			nil,
+			true,
+		},
+		{
+			"MissingColumns",
+			FormatClockify,
+			"Description,Start Date,Start Time\nPlanning,03/13/2025,08:00:00 AM\n",
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readTrackerEntries(strings.NewReader(test.records), test.format)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("readTrackerEntries() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if !test.wantErr && !reflect.DeepEqual(test.want, got) {
+				t.Errorf("readTrackerEntries() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRoundAndMergeEntries(t *testing.T) {
+	entries := []Entry{
+		{Date: "2025-03-13", Start: "09:15", End: "09:31", Note: "Standup"},
+		{Date: "2025-03-13", Start: "08:02", End: "09:14", Note: "Code review"},
+		{Date: "2025-03-13", Start: "09:33", End: "09:36", Note: "Chat"},
+		{Date: "2025-03-13", Start: "13:07", End: "16:58", Note: "Code review"},
+		{Date: "2025-03-14", Start: "08:00", End: "12:00"},
+	}
+	want := []Entry{
+		{Date: "2025-03-13", Start: "08:00", End: "09:30", Note: "Code review; Standup"},
+		{Date: "2025-03-13", Start: "13:00", End: "17:00", Note: "Code review"},
+		{Date: "2025-03-14", Start: "08:00", End: "12:00"},
+	}
+
+	rounded, err := roundEntries(entries, 15)
+	if err != nil {
+		t.Fatalf("roundEntries() error = %v", err)
+	}
+	got, err := mergeEntries(rounded)
+	if err != nil {
+		t.Fatalf("mergeEntries() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("roundEntries() and mergeEntries() = %v, want %v", got, want)
+	}
+}